@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -12,32 +13,52 @@ import (
 	"pr-reviewer-service/internal/api"
 	"pr-reviewer-service/internal/config"
 	"pr-reviewer-service/internal/database"
+	"pr-reviewer-service/internal/outbox"
 	"pr-reviewer-service/internal/repository"
+	"pr-reviewer-service/internal/repository/memory"
+	"pr-reviewer-service/internal/repository/sqlite"
 	"pr-reviewer-service/internal/service"
+	"pr-reviewer-service/internal/webhook"
 )
 
 func main() {
 	cfg := config.Load()
 
-	db, err := database.Connect(cfg.DatabaseURL)
-	if err != nil {
-		log.Fatalf("failed to connect to database: %v", err)
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+		return
 	}
 
-	if err := database.RunMigrations(db); err != nil {
-		db.Close()
-		log.Fatalf("failed to run migrations: %v", err)
+	store, err := openStorage(cfg)
+	if err != nil {
+		log.Fatalf("failed to open storage: %v", err)
 	}
-	defer db.Close()
+	defer store.close()
 
-	userRepo := repository.NewUserRepository(db)
-	teamRepo := repository.NewTeamRepository(db)
-	prRepo := repository.NewPRRepository(db)
+	svc := service.NewService(store.users, store.teams, store.prs, store.webhooks, store.tx)
 
-	svc := service.NewService(userRepo, teamRepo, prRepo)
+	outboxCfg := outbox.DefaultConfig()
+	outboxCfg.PollInterval = cfg.OutboxPollInterval
+	eventDispatcher := outbox.NewDispatcher(store.outbox, outboxCfg)
+	eventDispatcher.Register(webhook.NewSink(store.webhooks))
+
+	webhookCfg := webhook.DefaultConfig()
+	webhookCfg.PollInterval = cfg.WebhookPollInterval
+	webhookCfg.MaxAttempts = cfg.WebhookMaxAttempts
+	webhookDispatcher := webhook.NewDispatcher(store.webhooks, webhookCfg)
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go eventDispatcher.Run(workersCtx)
+	go webhookDispatcher.Run(workersCtx)
+	if cfg.TopUpInterval > 0 {
+		go svc.RunTopUp(workersCtx, cfg.TopUpInterval)
+	}
 
 	handler := api.NewHandler(svc)
-	router := api.SetupRoutes(handler)
+	router := api.SetupRoutes(handler, cfg.RequestTimeout)
 
 	go func() {
 		log.Printf("starting server on %s", cfg.ServerAddr)
@@ -51,6 +72,7 @@ func main() {
 	<-quit
 
 	log.Println("shutting down")
+	stopWorkers()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -65,3 +87,80 @@ func main() {
 
 	log.Println("server stopped")
 }
+
+type storage struct {
+	users    service.UserStorage
+	teams    service.TeamStorage
+	prs      service.PRStorage
+	webhooks webhookStorage
+	outbox   outbox.Store
+	tx       service.TxManager
+	close    func()
+}
+
+type webhookStorage interface {
+	service.WebhookStorage
+	webhook.Store
+	webhook.Enqueuer
+}
+
+// openStorage builds the repositories of the configured database driver.
+func openStorage(cfg *config.Config) (*storage, error) {
+	switch cfg.DatabaseDriver {
+	case database.DriverPostgres:
+		db, err := openDB(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := database.RunMigrations(db); err != nil {
+			db.Close()
+			return nil, err
+		}
+
+		return &storage{
+			users:    repository.NewUserRepository(db),
+			teams:    repository.NewTeamRepository(db),
+			prs:      repository.NewPRRepository(db),
+			webhooks: repository.NewWebhookRepository(db),
+			outbox:   repository.NewOutboxRepository(db),
+			tx:       repository.NewTxManager(db),
+			close:    func() { db.Close() },
+		}, nil
+	case database.DriverSQLite:
+		db, err := openDB(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := database.RunSQLiteMigrations(db); err != nil {
+			db.Close()
+			return nil, err
+		}
+
+		return &storage{
+			users:    sqlite.NewUserRepository(db),
+			teams:    sqlite.NewTeamRepository(db),
+			prs:      sqlite.NewPRRepository(db),
+			webhooks: sqlite.NewWebhookRepository(db),
+			outbox:   sqlite.NewOutboxRepository(db),
+			tx:       sqlite.NewTxManager(db),
+			close:    func() { db.Close() },
+		}, nil
+	case "memory":
+		log.Println("using in-memory storage, data will be lost on restart")
+		db := memory.New()
+
+		return &storage{
+			users:    memory.NewUserRepository(db),
+			teams:    memory.NewTeamRepository(db),
+			prs:      memory.NewPRRepository(db),
+			webhooks: memory.NewWebhookRepository(db),
+			outbox:   memory.NewOutboxRepository(db),
+			tx:       memory.NewTxManager(db),
+			close:    func() {},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.DatabaseDriver)
+	}
+}
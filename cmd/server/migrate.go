@@ -0,0 +1,85 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"pr-reviewer-service/internal/config"
+	"pr-reviewer-service/internal/database"
+)
+
+const migrateUsage = "usage: server migrate up | down [steps] | status"
+
+// runMigrate implements the migrate subcommand: up applies pending migrations,
+// down rolls back the given number of migrations (1 by default), status lists them.
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db, cfg.DatabaseDriver)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, upErr := migrator.Up()
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if upErr == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return upErr
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps %q: %s", args[1], migrateUsage)
+			}
+		}
+
+		rolledBack, err := migrator.Down(steps)
+		for _, migration := range rolledBack {
+			fmt.Printf("rolled back %04d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.AppliedAt != nil {
+				state = "applied at " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", status.Version, status.Name, state)
+		}
+		return nil
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+// openDB connects to the SQL database of the configured driver.
+func openDB(cfg *config.Config) (*sql.DB, error) {
+	switch cfg.DatabaseDriver {
+	case database.DriverPostgres:
+		return database.Connect(cfg.DatabaseURL)
+	case database.DriverSQLite:
+		return database.ConnectSQLite(cfg.DatabaseURL)
+	default:
+		return nil, fmt.Errorf("driver %q has no schema to migrate", cfg.DatabaseDriver)
+	}
+}
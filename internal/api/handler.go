@@ -1,6 +1,9 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"pr-reviewer-service/internal/service"
@@ -25,6 +28,29 @@ const (
 	CodeNotAssigned ErrorCode = "NOT_ASSIGNED"
 	CodeNoCandidate ErrorCode = "NO_CANDIDATE"
 	CodeNotFound    ErrorCode = "NOT_FOUND"
+
+	CodeUserInAnotherTeam ErrorCode = "USER_IN_ANOTHER_TEAM"
+	CodeHasOpenPRs        ErrorCode = "HAS_OPEN_PRS"
+
+	CodeAlreadyAssigned  ErrorCode = "ALREADY_ASSIGNED"
+	CodeReviewerIsAuthor ErrorCode = "REVIEWER_IS_AUTHOR"
+	CodeReviewerInactive ErrorCode = "REVIEWER_INACTIVE"
+	CodeNotTeamMember    ErrorCode = "NOT_TEAM_MEMBER"
+
+	CodeInvalidStrategy          ErrorCode = "INVALID_STRATEGY"
+	CodeInvalidReviewersCount    ErrorCode = "INVALID_REVIEWERS_COUNT"
+	CodeInvalidFallbackTeam      ErrorCode = "INVALID_FALLBACK_TEAM"
+	CodeInvalidReviewState       ErrorCode = "INVALID_REVIEW_STATE"
+	CodeInvalidRequiredApprovals ErrorCode = "INVALID_REQUIRED_APPROVALS"
+
+	CodeMergeBlocked       ErrorCode = "MERGE_BLOCKED"
+	CodeNotEnoughReviewers ErrorCode = "NOT_ENOUGH_REVIEWERS"
+	CodeInvalidTransition  ErrorCode = "INVALID_TRANSITION"
+	CodePRNotOpen          ErrorCode = "PR_NOT_OPEN"
+
+	CodeInvalidCursor ErrorCode = "INVALID_CURSOR"
+
+	CodeTimeout ErrorCode = "TIMEOUT"
 )
 
 type ErrorResponse struct {
@@ -47,17 +73,64 @@ func sendError(c *gin.Context, status int, code ErrorCode, message string) {
 }
 
 func handleServiceError(c *gin.Context, err error) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		sendError(c, http.StatusGatewayTimeout, CodeTimeout, "request deadline exceeded")
+		return
+	}
+
+	var blocked *service.MergeBlockedError
+	if errors.As(err, &blocked) {
+		sendError(c, http.StatusConflict, CodeMergeBlocked, blocked.Error())
+		return
+	}
+
+	var transition *service.TransitionError
+	if errors.As(err, &transition) {
+		sendError(c, http.StatusConflict, CodeInvalidTransition, transition.Error())
+		return
+	}
+
 	switch err {
 	case service.ErrTeamExists:
 		sendError(c, http.StatusBadRequest, CodeTeamExists, "team_name already exists")
 	case service.ErrPRExists:
 		sendError(c, http.StatusConflict, CodePRExists, "PR id already exists")
 	case service.ErrPRMerged:
-		sendError(c, http.StatusConflict, CodePRMerged, "cannot reassign on merged PR")
+		sendError(c, http.StatusConflict, CodePRMerged, "PR is merged")
+	case service.ErrPRNotOpen:
+		sendError(c, http.StatusConflict, CodePRNotOpen, "PR is not OPEN")
+	case service.ErrNotEnoughReviewers:
+		sendError(c, http.StatusConflict, CodeNotEnoughReviewers, "PR has fewer reviewers than the required approvals")
 	case service.ErrNotAssigned:
 		sendError(c, http.StatusConflict, CodeNotAssigned, "reviewer is not assigned to this PR")
 	case service.ErrNoCandidate:
 		sendError(c, http.StatusConflict, CodeNoCandidate, "no active replacement candidate in team")
+	case service.ErrUserInAnotherTeam:
+		sendError(c, http.StatusConflict, CodeUserInAnotherTeam, "user is a member of another team, transfer them instead")
+	case service.ErrHasOpenPRs:
+		sendError(c, http.StatusConflict, CodeHasOpenPRs, "user has OPEN pull requests as author or reviewer")
+	case service.ErrAlreadyAssigned:
+		sendError(c, http.StatusConflict, CodeAlreadyAssigned, "user is already assigned to this PR")
+	case service.ErrReviewerIsAuthor:
+		sendError(c, http.StatusConflict, CodeReviewerIsAuthor, "author cannot review their own PR")
+	case service.ErrReviewerInactive:
+		sendError(c, http.StatusConflict, CodeReviewerInactive, "reviewer is not active")
+	case service.ErrNotTeamMember:
+		sendError(c, http.StatusConflict, CodeNotTeamMember, "reviewer is not a member of the author's team or its fallback teams")
+	case service.ErrInvalidStrategy:
+		sendError(c, http.StatusBadRequest, CodeInvalidStrategy, "unknown reviewer_strategy")
+	case service.ErrInvalidReviewersCount:
+		sendError(c, http.StatusBadRequest, CodeInvalidReviewersCount,
+			fmt.Sprintf("reviewers_count must be between %d and %d", service.MinReviewersCount, service.MaxReviewersCount))
+	case service.ErrInvalidFallbackTeam:
+		sendError(c, http.StatusBadRequest, CodeInvalidFallbackTeam, "fallback_teams must list other existing teams, each once")
+	case service.ErrInvalidReviewState:
+		sendError(c, http.StatusBadRequest, CodeInvalidReviewState, "state must be one of APPROVED, CHANGES_REQUESTED, COMMENTED")
+	case service.ErrInvalidRequiredApprovals:
+		sendError(c, http.StatusBadRequest, CodeInvalidRequiredApprovals,
+			"required_approvals must be between 0 and reviewers_count")
+	case service.ErrInvalidCursor:
+		sendError(c, http.StatusBadRequest, CodeInvalidCursor, "invalid cursor")
 	case service.ErrNotFound:
 		sendError(c, http.StatusNotFound, CodeNotFound, "resource not found")
 	default:
@@ -1,7 +1,13 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net/http"
+	"time"
+
+	"pr-reviewer-service/internal/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,15 +16,52 @@ type CreatePRRequest struct {
 	PullRequestID   string `json:"pull_request_id" binding:"required"`
 	PullRequestName string `json:"pull_request_name" binding:"required"`
 	AuthorID        string `json:"author_id" binding:"required"`
+	ReviewersCount  int    `json:"reviewers_count"`
+	Draft           bool   `json:"draft"`
+}
+
+type TopUpReviewersRequest struct {
+	TeamName string `json:"team_name"`
 }
 
 type MergePRRequest struct {
 	PullRequestID string `json:"pull_request_id" binding:"required"`
+	// Force merges past the approval gate of the author's team.
+	Force bool `json:"force"`
+}
+
+type PRStatusRequest struct {
+	PullRequestID string `json:"pull_request_id" binding:"required"`
 }
 
 type ReassignRequest struct {
 	PullRequestID string `json:"pull_request_id" binding:"required"`
 	OldUserID     string `json:"old_user_id" binding:"required"`
+	NewUserID     string `json:"new_user_id"`
+}
+
+type PRReviewerRequest struct {
+	PullRequestID string `json:"pull_request_id" binding:"required"`
+	UserID        string `json:"user_id" binding:"required"`
+}
+
+type SubmitReviewRequest struct {
+	PullRequestID string `json:"pull_request_id" binding:"required"`
+	UserID        string `json:"user_id" binding:"required"`
+	State         string `json:"state" binding:"required"`
+}
+
+type ListPRsRequest struct {
+	Status      string     `form:"status" binding:"omitempty,oneof=DRAFT OPEN MERGED CLOSED"`
+	AuthorID    string     `form:"author_id"`
+	TeamName    string     `form:"team_name"`
+	ReviewerID  string     `form:"reviewer_id"`
+	CreatedFrom *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	SortBy      string     `form:"sort_by" binding:"omitempty,oneof=created_at pull_request_id"`
+	Order       string     `form:"order" binding:"omitempty,oneof=asc desc"`
+	Cursor      string     `form:"cursor"`
+	Limit       int        `form:"limit" binding:"omitempty,min=1"`
 }
 
 func (h *Handler) CreatePullRequest(c *gin.Context) {
@@ -28,7 +71,7 @@ func (h *Handler) CreatePullRequest(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.service.CreatePullRequest(req.PullRequestID, req.PullRequestName, req.AuthorID)
+	pr, err := h.service.CreatePullRequest(c.Request.Context(), req.PullRequestID, req.PullRequestName, req.AuthorID, req.ReviewersCount, req.Draft)
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -46,7 +89,39 @@ func (h *Handler) MergePullRequest(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.service.MergePullRequest(req.PullRequestID)
+	pr, err := h.service.MergePullRequest(c.Request.Context(), req.PullRequestID, req.Force)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pr": pr,
+	})
+}
+
+func (h *Handler) MarkReady(c *gin.Context) {
+	h.changeStatus(c, h.service.MarkReady)
+}
+
+func (h *Handler) ClosePullRequest(c *gin.Context) {
+	h.changeStatus(c, h.service.ClosePullRequest)
+}
+
+func (h *Handler) ReopenPullRequest(c *gin.Context) {
+	h.changeStatus(c, h.service.ReopenPullRequest)
+}
+
+// changeStatus serves the lifecycle endpoints, which differ only in the
+// service call.
+func (h *Handler) changeStatus(c *gin.Context, change func(ctx context.Context, prID string) (*models.PullRequest, error)) {
+	var req PRStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	pr, err := change(c.Request.Context(), req.PullRequestID)
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -64,7 +139,7 @@ func (h *Handler) ReassignReviewer(c *gin.Context) {
 		return
 	}
 
-	pr, replacedBy, err := h.service.ReassignReviewer(req.PullRequestID, req.OldUserID)
+	pr, replacedBy, err := h.service.ReassignReviewer(c.Request.Context(), req.PullRequestID, req.OldUserID, req.NewUserID)
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -75,3 +150,138 @@ func (h *Handler) ReassignReviewer(c *gin.Context) {
 		"replaced_by": replacedBy,
 	})
 }
+
+func (h *Handler) AddReviewer(c *gin.Context) {
+	var req PRReviewerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	pr, err := h.service.AddReviewer(c.Request.Context(), req.PullRequestID, req.UserID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pr": pr,
+	})
+}
+
+func (h *Handler) RemoveReviewer(c *gin.Context) {
+	var req PRReviewerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	pr, err := h.service.RemoveReviewer(c.Request.Context(), req.PullRequestID, req.UserID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pr": pr,
+	})
+}
+
+func (h *Handler) SubmitReview(c *gin.Context) {
+	var req SubmitReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	pr, err := h.service.SubmitReview(c.Request.Context(), req.PullRequestID, req.UserID, req.State)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pr": pr,
+	})
+}
+
+func (h *Handler) GetPullRequest(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	pr, err := h.service.GetPullRequest(c.Request.Context(), prID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pr": pr,
+	})
+}
+
+func (h *Handler) GetPullRequestHistory(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "pull_request_id is required")
+		return
+	}
+
+	history, err := h.service.GetPullRequestHistory(c.Request.Context(), prID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pull_request_id": prID,
+		"history":         history,
+	})
+}
+
+func (h *Handler) ListPullRequests(c *gin.Context) {
+	var req ListPRsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid query parameters")
+		return
+	}
+
+	page, err := h.service.ListPullRequests(c.Request.Context(), &models.PullRequestFilter{
+		Status:      req.Status,
+		AuthorID:    req.AuthorID,
+		TeamName:    req.TeamName,
+		ReviewerID:  req.ReviewerID,
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		SortBy:      req.SortBy,
+		Order:       req.Order,
+		Limit:       req.Limit,
+	}, req.Cursor)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// TopUpReviewers takes an optional body; without team_name it tops up pull
+// requests of all teams.
+func (h *Handler) TopUpReviewers(c *gin.Context) {
+	var req TopUpReviewersRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	result, err := h.service.TopUpReviewers(c.Request.Context(), req.TeamName)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -1,24 +1,72 @@
 package api
 
 import (
+	"context"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(h *Handler) *gin.Engine {
+// SetupRoutes registers the API. A positive requestTimeout bounds the context
+// every request is served with.
+func SetupRoutes(h *Handler, requestTimeout time.Duration) *gin.Engine {
 	r := gin.Default()
+	if requestTimeout > 0 {
+		r.Use(timeout(requestTimeout))
+	}
 
 	team := r.Group("/team")
 	team.POST("/add", h.CreateTeam)
 	team.GET("/get", h.GetTeam)
+	team.GET("/list", h.ListTeams)
+	team.POST("/updateSettings", h.UpdateTeamSettings)
+	team.POST("/deactivateUsers", h.DeactivateUsers)
+	team.POST("/addMembers", h.AddTeamMembers)
+	team.POST("/removeMembers", h.RemoveTeamMembers)
+	team.POST("/rename", h.RenameTeam)
+	team.POST("/delete", h.DeleteTeam)
 
 	users := r.Group("/users")
+	users.GET("/get", h.GetUser)
+	users.GET("/list", h.ListUsers)
 	users.POST("/setIsActive", h.SetUserActive)
 	users.GET("/getReview", h.GetUserReviews)
+	users.POST("/transfer", h.TransferUser)
 
 	pr := r.Group("/pullRequest")
 	pr.POST("/create", h.CreatePullRequest)
 	pr.POST("/merge", h.MergePullRequest)
+	pr.POST("/ready", h.MarkReady)
+	pr.POST("/close", h.ClosePullRequest)
+	pr.POST("/reopen", h.ReopenPullRequest)
 	pr.POST("/reassign", h.ReassignReviewer)
+	pr.POST("/addReviewer", h.AddReviewer)
+	pr.POST("/removeReviewer", h.RemoveReviewer)
+	pr.POST("/submitReview", h.SubmitReview)
+	pr.POST("/topUpReviewers", h.TopUpReviewers)
+	pr.GET("/get", h.GetPullRequest)
+	pr.GET("/history", h.GetPullRequestHistory)
+	pr.GET("/list", h.ListPullRequests)
+
+	webhooks := r.Group("/webhooks")
+	webhooks.POST("/register", h.RegisterWebhook)
+	webhooks.GET("/list", h.ListWebhooks)
+	webhooks.POST("/delete", h.DeleteWebhook)
+
+	stats := r.Group("/stats")
+	stats.GET("/reviewers", h.GetReviewerStats)
 
 	return r
 }
+
+// timeout gives the request context a deadline, so that storage calls are
+// cancelled once it passes or the client goes away.
+func timeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
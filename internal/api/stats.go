@@ -0,0 +1,26 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetReviewerStats(c *gin.Context) {
+	teamName := c.Query("team_name")
+
+	stats, err := h.service.GetReviewerStats(c.Request.Context(), teamName)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	response := gin.H{
+		"reviewers": stats,
+	}
+	if teamName != "" {
+		response["team_name"] = teamName
+	}
+
+	c.JSON(http.StatusOK, response)
+}
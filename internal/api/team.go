@@ -15,12 +15,12 @@ func (h *Handler) CreateTeam(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.CreateTeam(&team); err != nil {
+	if err := h.service.CreateTeam(c.Request.Context(), &team); err != nil {
 		handleServiceError(c, err)
 		return
 	}
 
-	createdTeam, err := h.service.GetTeam(team.TeamName)
+	createdTeam, err := h.service.GetTeam(c.Request.Context(), team.TeamName)
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -38,7 +38,7 @@ func (h *Handler) GetTeam(c *gin.Context) {
 		return
 	}
 
-	team, err := h.service.GetTeam(teamName)
+	team, err := h.service.GetTeam(c.Request.Context(), teamName)
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -46,3 +46,158 @@ func (h *Handler) GetTeam(c *gin.Context) {
 
 	c.JSON(http.StatusOK, team)
 }
+
+type ListTeamsRequest struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1"`
+}
+
+func (h *Handler) ListTeams(c *gin.Context) {
+	var req ListTeamsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid query parameters")
+		return
+	}
+
+	page, err := h.service.ListTeams(c.Request.Context(), req.Limit, req.Cursor)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+type UpdateTeamSettingsRequest struct {
+	TeamName string `json:"team_name" binding:"required"`
+	models.TeamSettingsPatch
+}
+
+func (h *Handler) UpdateTeamSettings(c *gin.Context) {
+	var req UpdateTeamSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	settings, err := h.service.UpdateTeamSettings(c.Request.Context(), req.TeamName, &req.TeamSettingsPatch)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"settings": settings,
+	})
+}
+
+type DeactivateUsersRequest struct {
+	TeamName string   `json:"team_name" binding:"required"`
+	UserIDs  []string `json:"user_ids" binding:"required,min=1"`
+}
+
+func (h *Handler) DeactivateUsers(c *gin.Context) {
+	var req DeactivateUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	result, err := h.service.DeactivateUsers(c.Request.Context(), req.TeamName, req.UserIDs)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+type TeamMembersRequest struct {
+	TeamName string              `json:"team_name" binding:"required"`
+	Members  []models.TeamMember `json:"members" binding:"required,min=1"`
+}
+
+func (h *Handler) AddTeamMembers(c *gin.Context) {
+	var req TeamMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	team, err := h.service.AddTeamMembers(c.Request.Context(), req.TeamName, req.Members)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team": team,
+	})
+}
+
+type RemoveTeamMembersRequest struct {
+	TeamName string   `json:"team_name" binding:"required"`
+	UserIDs  []string `json:"user_ids" binding:"required,min=1"`
+}
+
+func (h *Handler) RemoveTeamMembers(c *gin.Context) {
+	var req RemoveTeamMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	team, err := h.service.RemoveTeamMembers(c.Request.Context(), req.TeamName, req.UserIDs)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team": team,
+	})
+}
+
+type RenameTeamRequest struct {
+	TeamName    string `json:"team_name" binding:"required"`
+	NewTeamName string `json:"new_team_name" binding:"required"`
+}
+
+func (h *Handler) RenameTeam(c *gin.Context) {
+	var req RenameTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	team, err := h.service.RenameTeam(c.Request.Context(), req.TeamName, req.NewTeamName)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team": team,
+	})
+}
+
+type DeleteTeamRequest struct {
+	TeamName string `json:"team_name" binding:"required"`
+}
+
+func (h *Handler) DeleteTeam(c *gin.Context) {
+	var req DeleteTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if err := h.service.DeleteTeam(c.Request.Context(), req.TeamName); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team_name": req.TeamName,
+	})
+}
@@ -3,12 +3,15 @@ package api
 import (
 	"net/http"
 
+	"pr-reviewer-service/internal/models"
+
 	"github.com/gin-gonic/gin"
 )
 
 type SetIsActiveRequest struct {
-	UserID   string `json:"user_id" binding:"required"`
-	IsActive bool   `json:"is_active"`
+	UserID          string `json:"user_id" binding:"required"`
+	IsActive        bool   `json:"is_active"`
+	ReassignReviews bool   `json:"reassign_reviews"`
 }
 
 func (h *Handler) SetUserActive(c *gin.Context) {
@@ -18,7 +21,36 @@ func (h *Handler) SetUserActive(c *gin.Context) {
 		return
 	}
 
-	user, err := h.service.SetUserActive(req.UserID, req.IsActive)
+	user, result, err := h.service.SetUserActive(c.Request.Context(), req.UserID, req.IsActive, req.ReassignReviews)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	response := gin.H{
+		"user": user,
+	}
+	if result != nil {
+		response["reassigned"] = result.Reassigned
+		response["unreassigned"] = result.Unreassigned
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+type TransferUserRequest struct {
+	UserID   string `json:"user_id" binding:"required"`
+	TeamName string `json:"team_name" binding:"required"`
+}
+
+func (h *Handler) TransferUser(c *gin.Context) {
+	var req TransferUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	user, err := h.service.TransferUser(c.Request.Context(), req.UserID, req.TeamName)
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -36,7 +68,7 @@ func (h *Handler) GetUserReviews(c *gin.Context) {
 		return
 	}
 
-	prs, err := h.service.GetUserReviews(userID)
+	prs, err := h.service.GetUserReviews(c.Request.Context(), userID)
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -47,3 +79,48 @@ func (h *Handler) GetUserReviews(c *gin.Context) {
 		"pull_requests": prs,
 	})
 }
+
+func (h *Handler) GetUser(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "user_id is required")
+		return
+	}
+
+	user, err := h.service.GetUser(c.Request.Context(), userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": user,
+	})
+}
+
+type ListUsersRequest struct {
+	TeamName string `form:"team_name"`
+	IsActive *bool  `form:"is_active"`
+	Cursor   string `form:"cursor"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1"`
+}
+
+func (h *Handler) ListUsers(c *gin.Context) {
+	var req ListUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid query parameters")
+		return
+	}
+
+	page, err := h.service.ListUsers(c.Request.Context(), &models.UserFilter{
+		TeamName: req.TeamName,
+		IsActive: req.IsActive,
+		Limit:    req.Limit,
+	}, req.Cursor)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+
+	"pr-reviewer-service/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RegisterWebhookRequest struct {
+	TeamName string `json:"team_name" binding:"required"`
+	URL      string `json:"url" binding:"required,url"`
+	Secret   string `json:"secret"`
+}
+
+type DeleteWebhookRequest struct {
+	ID int64 `json:"id" binding:"required"`
+}
+
+func (h *Handler) RegisterWebhook(c *gin.Context) {
+	var req RegisterWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	webhook := &models.Webhook{
+		TeamName: req.TeamName,
+		URL:      req.URL,
+		Secret:   req.Secret,
+	}
+	if err := h.service.RegisterWebhook(c.Request.Context(), webhook); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"webhook": webhook,
+	})
+}
+
+func (h *Handler) ListWebhooks(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "team_name is required")
+		return
+	}
+
+	webhooks, err := h.service.ListWebhooks(c.Request.Context(), teamName)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"team_name": teamName,
+		"webhooks":  webhooks,
+	})
+}
+
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	var req DeleteWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		sendError(c, http.StatusBadRequest, "INVALID_REQUEST", "invalid request body")
+		return
+	}
+
+	if err := h.service.DeleteWebhook(c.Request.Context(), req.ID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id": req.ID,
+	})
+}
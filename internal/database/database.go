@@ -8,7 +8,7 @@ import (
 )
 
 func Connect(dbURL string) (*sql.DB, error) {
-	db, err := sql.Open("postgres", dbURL)
+	db, err := sql.Open(DriverPostgres, dbURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -18,50 +18,19 @@ func Connect(dbURL string) (*sql.DB, error) {
 	return db, nil
 }
 
+// RunMigrations applies all pending Postgres migrations.
 func RunMigrations(db *sql.DB) error {
-	migrations := []string{
-		// create teams table
-		`CREATE TABLE IF NOT EXISTS teams (
-			team_name VARCHAR(255) PRIMARY KEY,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-		// create users table
-		`CREATE TABLE IF NOT EXISTS users (
-			user_id VARCHAR(255) PRIMARY KEY,
-			username VARCHAR(255) NOT NULL,
-			team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name),
-			is_active BOOLEAN NOT NULL DEFAULT true,
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)`,
-		// create pull_requests table
-		`CREATE TABLE IF NOT EXISTS pull_requests (
-			pull_request_id VARCHAR(255) PRIMARY KEY,
-			pull_request_name VARCHAR(255) NOT NULL,
-			author_id VARCHAR(255) NOT NULL REFERENCES users(user_id),
-			status VARCHAR(10) NOT NULL CHECK (status IN ('OPEN', 'MERGED')),
-			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			merged_at TIMESTAMP
-		)`,
-		// create reviewers table (many-to-many relationship)
-		`CREATE TABLE IF NOT EXISTS pr_reviewers (
-			pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id),
-			user_id VARCHAR(255) NOT NULL REFERENCES users(user_id),
-			assigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			PRIMARY KEY (pull_request_id, user_id)
-		)`,
-		// create indexes for better performance
-		`CREATE INDEX IF NOT EXISTS idx_users_team_name ON users(team_name)`,
-		`CREATE INDEX IF NOT EXISTS idx_users_is_active ON users(is_active)`,
-		`CREATE INDEX IF NOT EXISTS idx_pr_status ON pull_requests(status)`,
-		`CREATE INDEX IF NOT EXISTS idx_pr_reviewers_user_id ON pr_reviewers(user_id)`,
-	}
+	return migrateUp(db, DriverPostgres)
+}
 
-	for _, migration := range migrations {
-		if _, err := db.Exec(migration); err != nil {
-			return fmt.Errorf("failed to execute migration: %w", err)
-		}
+func migrateUp(db *sql.DB, driver string) error {
+	migrator, err := NewMigrator(db, driver)
+	if err != nil {
+		return err
 	}
 
+	if _, err := migrator.Up(); err != nil {
+		return err
+	}
 	return nil
 }
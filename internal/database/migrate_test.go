@@ -0,0 +1,96 @@
+package database_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"pr-reviewer-service/internal/database"
+)
+
+func TestMigrator(t *testing.T) {
+	db, err := database.ConnectSQLite(filepath.Join(t.TempDir(), "migrate.db"))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db, database.DriverSQLite)
+	if err != nil {
+		t.Fatalf("new migrator: %v", err)
+	}
+
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if len(applied) == 0 {
+		t.Fatal("expected migrations to be applied")
+	}
+
+	t.Run("up is idempotent", func(t *testing.T) {
+		applied, err := migrator.Up()
+		if err != nil {
+			t.Fatalf("up: %v", err)
+		}
+		if len(applied) != 0 {
+			t.Errorf("expected nothing to apply, got %v", applied)
+		}
+	})
+
+	t.Run("status", func(t *testing.T) {
+		statuses, err := migrator.Status()
+		if err != nil {
+			t.Fatalf("status: %v", err)
+		}
+		for _, status := range statuses {
+			if status.AppliedAt == nil {
+				t.Errorf("expected migration %d_%s to be applied", status.Version, status.Name)
+			}
+		}
+	})
+
+	t.Run("foreign keys stay enforced", func(t *testing.T) {
+		if _, err := db.Exec(`INSERT INTO users (user_id, username, team_name) VALUES ('u1', 'u1', 'missing')`); err == nil {
+			t.Error("expected a user of a missing team to be rejected")
+		}
+		if _, err := db.Exec(`INSERT INTO users (user_id, username, team_name) VALUES ('u1', 'u1', NULL)`); err != nil {
+			t.Errorf("expected a user without a team to be accepted: %v", err)
+		}
+		if _, err := db.Exec(`DELETE FROM users`); err != nil {
+			t.Fatalf("cleanup: %v", err)
+		}
+	})
+
+	t.Run("down and up again", func(t *testing.T) {
+		rolledBack, err := migrator.Down(len(applied))
+		if err != nil {
+			t.Fatalf("down: %v", err)
+		}
+		if len(rolledBack) != len(applied) {
+			t.Fatalf("expected %d migrations rolled back, got %d", len(applied), len(rolledBack))
+		}
+
+		var tables int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'teams'`).Scan(&tables); err != nil {
+			t.Fatalf("query: %v", err)
+		}
+		if tables != 0 {
+			t.Error("expected teams table to be dropped")
+		}
+
+		if _, err := migrator.Up(); err != nil {
+			t.Fatalf("up: %v", err)
+		}
+		if _, err := db.Exec(`INSERT INTO teams (team_name) VALUES ('backend')`); err != nil {
+			t.Errorf("expected schema to be usable after re-applying: %v", err)
+		}
+	})
+}
+
+func TestMigrationsOfEveryDriverLoad(t *testing.T) {
+	for _, driver := range []string{database.DriverPostgres, database.DriverSQLite} {
+		if _, err := database.NewMigrator(nil, driver); err != nil {
+			t.Errorf("%s: %v", driver, err)
+		}
+	}
+}
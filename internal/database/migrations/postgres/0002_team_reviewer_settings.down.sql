@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS required_reviewers;
+ALTER TABLE teams DROP COLUMN IF EXISTS reviewers_count;
+ALTER TABLE teams DROP COLUMN IF EXISTS reviewer_strategy;
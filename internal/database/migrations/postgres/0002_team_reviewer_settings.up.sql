@@ -0,0 +1,6 @@
+-- add reviewer selection strategy to teams
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS reviewer_strategy VARCHAR(32) NOT NULL DEFAULT 'random';
+
+-- add required reviewers count to teams (default) and pull requests (effective value)
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS reviewers_count INTEGER NOT NULL DEFAULT 2 CHECK (reviewers_count BETWEEN 1 AND 5);
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS required_reviewers INTEGER NOT NULL DEFAULT 2;
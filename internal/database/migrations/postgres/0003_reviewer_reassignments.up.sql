@@ -0,0 +1,10 @@
+-- create reviewer reassignments log
+CREATE TABLE IF NOT EXISTS pr_reviewer_reassignments (
+    id SERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id),
+    old_user_id VARCHAR(255) NOT NULL REFERENCES users(user_id),
+    new_user_id VARCHAR(255) NOT NULL REFERENCES users(user_id),
+    reassigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_reviewer_reassignments_old_user_id ON pr_reviewer_reassignments(old_user_id);
@@ -0,0 +1,2 @@
+-- fails while users without a team exist; move them into a team first
+ALTER TABLE users ALTER COLUMN team_name SET NOT NULL;
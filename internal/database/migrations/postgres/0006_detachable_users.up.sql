@@ -0,0 +1,2 @@
+-- allow users to leave their team without losing their review history
+ALTER TABLE users ALTER COLUMN team_name DROP NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS from_fallback;
+DROP TABLE IF EXISTS team_fallbacks;
@@ -0,0 +1,4 @@
+-- the decision each reviewer has submitted on the pull request so far
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS review_state VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+    CHECK (review_state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED'));
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
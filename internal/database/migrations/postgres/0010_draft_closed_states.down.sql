@@ -0,0 +1,4 @@
+-- fails while DRAFT or CLOSED pull requests exist; open or delete them first
+ALTER TABLE pull_requests DROP CONSTRAINT IF EXISTS pull_requests_status_check;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_status_check
+    CHECK (status IN ('OPEN', 'MERGED'));
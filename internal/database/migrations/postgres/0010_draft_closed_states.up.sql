@@ -0,0 +1,4 @@
+-- allow draft pull requests and pull requests closed without a merge
+ALTER TABLE pull_requests DROP CONSTRAINT IF EXISTS pull_requests_status_check;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_status_check
+    CHECK (status IN ('DRAFT', 'OPEN', 'MERGED', 'CLOSED'));
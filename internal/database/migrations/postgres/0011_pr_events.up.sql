@@ -0,0 +1,14 @@
+-- create pull request history: one row per change, in the order they happened
+CREATE TABLE IF NOT EXISTS pr_events (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id),
+    action VARCHAR(32) NOT NULL,
+    user_id VARCHAR(255),
+    old_user_id VARCHAR(255),
+    status VARCHAR(10),
+    review_state VARCHAR(20),
+    reason VARCHAR(32),
+    occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_events_pull_request_id ON pr_events(pull_request_id, id);
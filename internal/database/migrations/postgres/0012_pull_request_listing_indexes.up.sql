@@ -0,0 +1,3 @@
+-- support keyset pagination of /pullRequest/list, with and without a status filter
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at ON pull_requests(created_at, pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status_created_at ON pull_requests(status, created_at, pull_request_id);
@@ -0,0 +1,91 @@
+-- create teams table
+CREATE TABLE IF NOT EXISTS teams (
+    team_name VARCHAR(255) PRIMARY KEY,
+    reviewer_strategy VARCHAR(32) NOT NULL DEFAULT 'random',
+    reviewers_count INTEGER NOT NULL DEFAULT 2 CHECK (reviewers_count BETWEEN 1 AND 5),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- create users table
+CREATE TABLE IF NOT EXISTS users (
+    user_id VARCHAR(255) PRIMARY KEY,
+    username VARCHAR(255) NOT NULL,
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- create pull_requests table
+CREATE TABLE IF NOT EXISTS pull_requests (
+    pull_request_id VARCHAR(255) PRIMARY KEY,
+    pull_request_name VARCHAR(255) NOT NULL,
+    author_id VARCHAR(255) NOT NULL REFERENCES users(user_id),
+    status VARCHAR(10) NOT NULL CHECK (status IN ('OPEN', 'MERGED')),
+    required_reviewers INTEGER NOT NULL DEFAULT 2,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    merged_at TIMESTAMP
+);
+
+-- create reviewers table (many-to-many relationship)
+CREATE TABLE IF NOT EXISTS pr_reviewers (
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id),
+    user_id VARCHAR(255) NOT NULL REFERENCES users(user_id),
+    assigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (pull_request_id, user_id)
+);
+
+-- create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_users_team_name ON users(team_name);
+CREATE INDEX IF NOT EXISTS idx_users_is_active ON users(is_active);
+CREATE INDEX IF NOT EXISTS idx_pr_status ON pull_requests(status);
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers_user_id ON pr_reviewers(user_id);
+
+-- create reviewer reassignments log
+CREATE TABLE IF NOT EXISTS pr_reviewer_reassignments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id),
+    old_user_id VARCHAR(255) NOT NULL REFERENCES users(user_id),
+    new_user_id VARCHAR(255) NOT NULL REFERENCES users(user_id),
+    reassigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_pr_reviewer_reassignments_old_user_id ON pr_reviewer_reassignments(old_user_id);
+
+-- create webhooks and their delivery outbox
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name),
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_webhooks_team_name ON webhooks(team_name);
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON webhook_deliveries(next_attempt_at) WHERE status = 'PENDING';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_event ON webhook_deliveries(webhook_id, event_id);
+
+-- create transactional outbox of domain events
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id VARCHAR(64) NOT NULL UNIQUE,
+    event_type VARCHAR(64) NOT NULL,
+    team_name VARCHAR(255) NOT NULL,
+    pull_request_id VARCHAR(255) NOT NULL,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP,
+    processed_at TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_unprocessed ON outbox_events(id) WHERE processed_at IS NULL;
@@ -0,0 +1,17 @@
+-- allow users to leave their team without losing their review history;
+-- SQLite cannot drop NOT NULL in place, so the table is rebuilt
+CREATE TABLE users_new (
+    user_id VARCHAR(255) PRIMARY KEY,
+    username VARCHAR(255) NOT NULL,
+    team_name VARCHAR(255) REFERENCES teams(team_name),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO users_new (user_id, username, team_name, is_active, created_at, updated_at)
+    SELECT user_id, username, team_name, is_active, created_at, updated_at FROM users;
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+
+CREATE INDEX IF NOT EXISTS idx_users_team_name ON users(team_name);
+CREATE INDEX IF NOT EXISTS idx_users_is_active ON users(is_active);
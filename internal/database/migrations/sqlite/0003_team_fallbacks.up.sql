@@ -0,0 +1,13 @@
+-- teams asked for reviewers, in order, when the home team cannot supply enough
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name),
+    fallback_team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name),
+    position INTEGER NOT NULL,
+    PRIMARY KEY (team_name, fallback_team_name),
+    CHECK (team_name <> fallback_team_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_fallbacks_fallback_team_name ON team_fallbacks(fallback_team_name);
+
+-- remember which reviewers were borrowed from a fallback team
+ALTER TABLE pr_reviewers ADD COLUMN from_fallback BOOLEAN NOT NULL DEFAULT false;
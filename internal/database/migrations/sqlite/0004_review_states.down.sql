@@ -0,0 +1,2 @@
+ALTER TABLE pr_reviewers DROP COLUMN reviewed_at;
+ALTER TABLE pr_reviewers DROP COLUMN review_state;
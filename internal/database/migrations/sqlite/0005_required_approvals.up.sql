@@ -0,0 +1,2 @@
+-- approvals a pull request of the team needs before it can be merged, 0 turns the gate off
+ALTER TABLE teams ADD COLUMN required_approvals INTEGER NOT NULL DEFAULT 0 CHECK (required_approvals BETWEEN 0 AND 5);
@@ -0,0 +1,16 @@
+-- fails while DRAFT or CLOSED pull requests exist; open or delete them first
+CREATE TABLE pull_requests_new (
+    pull_request_id VARCHAR(255) PRIMARY KEY,
+    pull_request_name VARCHAR(255) NOT NULL,
+    author_id VARCHAR(255) NOT NULL REFERENCES users(user_id),
+    status VARCHAR(10) NOT NULL CHECK (status IN ('OPEN', 'MERGED')),
+    required_reviewers INTEGER NOT NULL DEFAULT 2,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    merged_at TIMESTAMP
+);
+INSERT INTO pull_requests_new (pull_request_id, pull_request_name, author_id, status, required_reviewers, created_at, merged_at)
+    SELECT pull_request_id, pull_request_name, author_id, status, required_reviewers, created_at, merged_at FROM pull_requests;
+DROP TABLE pull_requests;
+ALTER TABLE pull_requests_new RENAME TO pull_requests;
+
+CREATE INDEX IF NOT EXISTS idx_pr_status ON pull_requests(status);
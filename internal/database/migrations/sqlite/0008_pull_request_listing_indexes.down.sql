@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_pull_requests_status_created_at;
+DROP INDEX IF EXISTS idx_pull_requests_created_at;
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "modernc.org/sqlite"
+)
+
+// ConnectSQLite opens the SQLite database file at path, creating it if needed.
+func ConnectSQLite(path string) (*sql.DB, error) {
+	dsn := "file:" + path +
+		"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_time_format=sqlite"
+
+	db, err := sql.Open(DriverSQLite, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	// SQLite allows a single writer; one connection avoids SQLITE_BUSY between our own transactions
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return db, nil
+}
+
+// RunSQLiteMigrations applies all pending SQLite migrations.
+func RunSQLiteMigrations(db *sql.DB) error {
+	return migrateUp(db, DriverSQLite)
+}
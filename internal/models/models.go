@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type User struct {
 	UserID   string `json:"user_id" db:"user_id"`
@@ -10,8 +13,28 @@ type User struct {
 }
 
 type Team struct {
-	TeamName string       `json:"team_name" db:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName         string       `json:"team_name" db:"team_name"`
+	ReviewerStrategy string       `json:"reviewer_strategy,omitempty" db:"reviewer_strategy"`
+	ReviewersCount   int          `json:"reviewers_count,omitempty" db:"reviewers_count"`
+	Members          []TeamMember `json:"members"`
+}
+
+// TeamSettings configures reviewer selection for a team. FallbackTeams are
+// asked in order for reviewers when the team itself cannot supply enough.
+// RequiredApprovals above zero gates merging of the team's pull requests.
+type TeamSettings struct {
+	TeamName          string   `json:"team_name" db:"team_name"`
+	ReviewerStrategy  string   `json:"reviewer_strategy" db:"reviewer_strategy"`
+	ReviewersCount    int      `json:"reviewers_count" db:"reviewers_count"`
+	RequiredApprovals int      `json:"required_approvals" db:"required_approvals"`
+	FallbackTeams     []string `json:"fallback_teams"`
+}
+
+type TeamSettingsPatch struct {
+	ReviewerStrategy  *string   `json:"reviewer_strategy"`
+	ReviewersCount    *int      `json:"reviewers_count"`
+	RequiredApprovals *int      `json:"required_approvals"`
+	FallbackTeams     *[]string `json:"fallback_teams"`
 }
 
 type TeamMember struct {
@@ -28,6 +51,226 @@ type PullRequest struct {
 	AuthorID          string     `json:"author_id" db:"author_id"`
 	Status            string     `json:"status" db:"status"`
 	AssignedReviewers []string   `json:"assigned_reviewers"`
+	RequiredReviewers int        `json:"required_reviewers" db:"required_reviewers"`
+	// FallbackReviewers are the assigned reviewers that come from a fallback
+	// team rather than the author's team.
+	FallbackReviewers []string   `json:"fallback_reviewers,omitempty"`
+	Reviewers         []Reviewer `json:"reviewers,omitempty"`
+}
+
+// Reviewer is an assigned reviewer with the decision they submitted last;
+// ReviewedAt is nil while the review is PENDING.
+type Reviewer struct {
+	AssignedAt   *time.Time `json:"assigned_at,omitempty" db:"assigned_at"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	UserID       string     `json:"user_id" db:"user_id"`
+	Username     string     `json:"username" db:"username"`
+	FromFallback bool       `json:"from_fallback" db:"from_fallback"`
+	ReviewState  string     `json:"review_state" db:"review_state"`
+}
+
+// TeamSummary is a team with the size of its roster, as listed by /team/list.
+type TeamSummary struct {
+	TeamName           string `json:"team_name" db:"team_name"`
+	ReviewerStrategy   string `json:"reviewer_strategy" db:"reviewer_strategy"`
+	ReviewersCount     int    `json:"reviewers_count" db:"reviewers_count"`
+	MembersCount       int    `json:"members_count" db:"members_count"`
+	ActiveMembersCount int    `json:"active_members_count" db:"active_members_count"`
+}
+
+// TeamFilter pages through teams ordered by name; After is the last team_name
+// of the previous page.
+type TeamFilter struct {
+	After string
+	Limit int
+}
+
+type TeamPage struct {
+	Teams      []TeamSummary `json:"teams"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// UserFilter pages through users ordered by user_id; After is the last user_id
+// of the previous page.
+type UserFilter struct {
+	TeamName string
+	IsActive *bool
+	After    string
+	Limit    int
+}
+
+type UserPage struct {
+	Users      []User `json:"users"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type PullRequestFilter struct {
+	Status      string
+	AuthorID    string
+	TeamName    string
+	ReviewerID  string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	SortBy      string
+	Order       string
+	After       *PullRequestCursor
+	Limit       int
+}
+
+// PullRequestCursor points at the last pull request of a page, keyset pagination
+// continues strictly after it in the requested order. SortBy and Order record
+// the order the cursor was issued for; it is rejected under any other.
+type PullRequestCursor struct {
+	CreatedAt     time.Time `json:"created_at"`
+	PullRequestID string    `json:"pull_request_id"`
+	SortBy        string    `json:"sort_by"`
+	Order         string    `json:"order"`
+}
+
+type PullRequestPage struct {
+	PullRequests []PullRequest `json:"pull_requests"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
+}
+
+type ReviewerStats struct {
+	UserID                string   `json:"user_id" db:"user_id"`
+	Username              string   `json:"username" db:"username"`
+	TeamName              string   `json:"team_name" db:"team_name"`
+	TotalAssignments      int      `json:"total_assignments" db:"total_assignments"`
+	OpenAssignments       int      `json:"open_assignments" db:"open_assignments"`
+	ReassignedAway        int      `json:"reassigned_away" db:"reassigned_away"`
+	AvgTimeToMergeSeconds *float64 `json:"avg_time_to_merge_seconds" db:"avg_time_to_merge_seconds"`
+}
+
+// OpenReview is a reviewer assignment on an OPEN pull request.
+type OpenReview struct {
+	PullRequestID string `db:"pull_request_id"`
+	AuthorID      string `db:"author_id"`
+	ReviewerID    string `db:"user_id"`
+}
+
+// ReviewerReplacement is a reviewer handed over to another one. FromFallback
+// flags a new reviewer borrowed from a fallback team.
+type ReviewerReplacement struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	NewUserID     string `json:"new_user_id"`
+	FromFallback  bool   `json:"-"`
+}
+
+// ReviewerAssignment is a reviewer assigned to a pull request.
+type ReviewerAssignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+// TopUpResult lists the reviewers added to under-staffed pull requests and the
+// pull requests that still have fewer reviewers than required.
+type TopUpResult struct {
+	Assigned     []ReviewerAssignment `json:"assigned"`
+	Understaffed []string             `json:"understaffed"`
+}
+
+type UnreassignedReview struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+type DeactivationResult struct {
+	DeactivatedUsers []string              `json:"deactivated_users"`
+	Reassigned       []ReviewerReplacement `json:"reassigned"`
+	Unreassigned     []UnreassignedReview  `json:"unreassigned"`
+}
+
+// Event describes something that happened to a pull request and is delivered to
+// the webhooks of the author's team.
+type Event struct {
+	OccurredAt    time.Time       `json:"occurred_at"`
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	TeamName      string          `json:"team_name"`
+	PullRequestID string          `json:"pull_request_id"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// OutboxEvent is an event stored in the outbox and waiting to be handed to sinks.
+type OutboxEvent struct {
+	ID    int64
+	Event Event
+}
+
+type ReviewersAssignedData struct {
+	PullRequest *PullRequest `json:"pull_request"`
+}
+
+type ReviewerReassignedData struct {
+	PullRequest *PullRequest `json:"pull_request"`
+	OldUserID   string       `json:"old_user_id"`
+	NewUserID   string       `json:"new_user_id"`
+}
+
+// ReviewersAddedData describes reviewers assigned on top of the current ones.
+type ReviewersAddedData struct {
+	PullRequest *PullRequest `json:"pull_request"`
+	UserIDs     []string     `json:"user_ids"`
+}
+
+type ReviewerRemovedData struct {
+	PullRequest *PullRequest `json:"pull_request"`
+	UserID      string       `json:"user_id"`
+}
+
+type ReviewSubmittedData struct {
+	PullRequest *PullRequest `json:"pull_request"`
+	UserID      string       `json:"user_id"`
+	ReviewState string       `json:"review_state"`
+}
+
+// PullRequestMergedData describes a merge; Forced is set when the merge
+// bypassed the approval gate.
+type PullRequestMergedData struct {
+	PullRequest *PullRequest `json:"pull_request"`
+	Forced      bool         `json:"forced,omitempty"`
+}
+
+// PullRequestStatusData describes a lifecycle transition other than a merge.
+type PullRequestStatusData struct {
+	PullRequest    *PullRequest `json:"pull_request"`
+	PreviousStatus string       `json:"previous_status"`
+}
+
+// HistoryEntry is one change in the audit trail of a pull request. Which of
+// the optional fields are set depends on Action: UserID for reviewer changes
+// and reviews, OldUserID for reassignments, Status for creation and status
+// changes, ReviewState for reviews. Reason tells why a reviewer was assigned,
+// removed or replaced, and marks forced merges.
+type HistoryEntry struct {
+	OccurredAt    time.Time `json:"occurred_at"`
+	PullRequestID string    `json:"pull_request_id"`
+	Action        string    `json:"action"`
+	UserID        string    `json:"user_id,omitempty"`
+	OldUserID     string    `json:"old_user_id,omitempty"`
+	Status        string    `json:"status,omitempty"`
+	ReviewState   string    `json:"review_state,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+}
+
+type Webhook struct {
+	CreatedAt *time.Time `json:"created_at,omitempty" db:"created_at"`
+	ID        int64      `json:"id" db:"id"`
+	TeamName  string     `json:"team_name" db:"team_name"`
+	URL       string     `json:"url" db:"url"`
+	Secret    string     `json:"secret,omitempty" db:"secret"`
+}
+
+// WebhookDelivery is a pending attempt to deliver one event to one webhook.
+type WebhookDelivery struct {
+	ID        int64  `db:"id"`
+	EventType string `db:"event_type"`
+	Payload   []byte `db:"payload"`
+	Attempts  int    `db:"attempts"`
+	URL       string `db:"url"`
+	Secret    string `db:"secret"`
 }
 
 type PullRequestShort struct {
@@ -35,9 +278,72 @@ type PullRequestShort struct {
 	PullRequestName string `json:"pull_request_name" db:"pull_request_name"`
 	AuthorID        string `json:"author_id" db:"author_id"`
 	Status          string `json:"status" db:"status"`
+	// ReviewState is the reviewer's decision when listed by /users/getReview.
+	ReviewState string `json:"review_state,omitempty" db:"review_state"`
 }
 
 const (
+	StatusDraft  = "DRAFT"
 	StatusOpen   = "OPEN"
 	StatusMerged = "MERGED"
+	StatusClosed = "CLOSED"
+)
+
+const (
+	ReviewPending          = "PENDING"
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+	ReviewCommented        = "COMMENTED"
+)
+
+const (
+	SortByCreatedAt     = "created_at"
+	SortByPullRequestID = "pull_request_id"
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+)
+
+const (
+	EventReviewersAssigned   = "pull_request.reviewers_assigned"
+	EventReviewerReassigned  = "pull_request.reviewer_reassigned"
+	EventPullRequestMerged   = "pull_request.merged"
+	EventReviewersAdded      = "pull_request.reviewers_added"
+	EventReviewerRemoved     = "pull_request.reviewer_removed"
+	EventReviewSubmitted     = "pull_request.review_submitted"
+	EventPullRequestReady    = "pull_request.ready_for_review"
+	EventPullRequestClosed   = "pull_request.closed"
+	EventPullRequestReopened = "pull_request.reopened"
+)
+
+const (
+	HistoryCreated            = "created"
+	HistoryStatusChanged      = "status_changed"
+	HistoryReviewerAssigned   = "reviewer_assigned"
+	HistoryReviewerReassigned = "reviewer_reassigned"
+	HistoryReviewerRemoved    = "reviewer_removed"
+	HistoryReviewSubmitted    = "review_submitted"
+)
+
+// Reasons recorded in the history: why a reviewer changed, or that a merge
+// bypassed the approval gate.
+const (
+	ReasonAuto        = "auto"
+	ReasonManual      = "manual"
+	ReasonTopUp       = "top_up"
+	ReasonDeactivated = "deactivated"
+	ReasonClosed      = "closed"
+	ReasonForced      = "forced"
+)
+
+const (
+	DeliveryPending   = "PENDING"
+	DeliveryDelivered = "DELIVERED"
+	DeliveryFailed    = "FAILED"
 )
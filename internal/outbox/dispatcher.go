@@ -0,0 +1,126 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// Sink consumes domain events. Delivery is at-least-once, so Handle must be
+// idempotent: an event is handed over again if any sink fails on it or the
+// process stops before the event is marked processed.
+type Sink interface {
+	Name() string
+	Handle(ctx context.Context, event *models.Event) error
+}
+
+// Store is the persistence the dispatcher needs, implemented by repository.OutboxRepository.
+type Store interface {
+	ClaimBatch(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEvent, error)
+	MarkProcessed(ctx context.Context, ids []int64) error
+	Release(ctx context.Context, ids []int64, retryAt time.Time) error
+}
+
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	Lease        time.Duration
+	RetryDelay   time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		Lease:        time.Minute,
+		RetryDelay:   5 * time.Second,
+	}
+}
+
+// Dispatcher reads committed events from the outbox and hands them to the
+// registered sinks in the order they were written.
+type Dispatcher struct {
+	store Store
+	sinks []Sink
+	cfg   Config
+	now   func() time.Time
+}
+
+func NewDispatcher(store Store, cfg Config) *Dispatcher {
+	return &Dispatcher{
+		store: store,
+		cfg:   cfg,
+		now:   time.Now,
+	}
+}
+
+// Register adds a sink. Sinks must be registered before Run is started.
+func (d *Dispatcher) Register(sink Sink) {
+	d.sinks = append(d.sinks, sink)
+}
+
+// Run polls the outbox until ctx is canceled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DispatchBatch(ctx); err != nil {
+			log.Printf("outbox dispatch failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchBatch hands one batch of events to the sinks. Processing stops at the
+// first event a sink fails on; it and the rest of the batch are retried after
+// RetryDelay so that sinks keep seeing events in order.
+func (d *Dispatcher) DispatchBatch(ctx context.Context) error {
+	events, err := d.store.ClaimBatch(ctx, d.now(), d.cfg.Lease, d.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	processed := make([]int64, 0, len(events))
+	var failed []int64
+	var handleErr error
+
+	for i := range events {
+		if handleErr == nil && ctx.Err() == nil {
+			handleErr = d.handle(ctx, &events[i].Event)
+			if handleErr == nil {
+				processed = append(processed, events[i].ID)
+				continue
+			}
+		}
+		failed = append(failed, events[i].ID)
+	}
+
+	// record the outcome even if ctx was cancelled meanwhile, so that handled events are not redelivered
+	bookkeeping := context.WithoutCancel(ctx)
+	if err := d.store.MarkProcessed(bookkeeping, processed); err != nil {
+		return err
+	}
+	if err := d.store.Release(bookkeeping, failed, d.now().Add(d.cfg.RetryDelay)); err != nil {
+		return err
+	}
+
+	return handleErr
+}
+
+func (d *Dispatcher) handle(ctx context.Context, event *models.Event) error {
+	for _, sink := range d.sinks {
+		if err := sink.Handle(ctx, event); err != nil {
+			log.Printf("sink %s failed on event %s: %v", sink.Name(), event.ID, err)
+			return err
+		}
+	}
+	return nil
+}
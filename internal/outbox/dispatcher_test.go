@@ -0,0 +1,117 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/outbox"
+)
+
+type fakeStore struct {
+	events    []models.OutboxEvent
+	processed map[int64]bool
+	released  map[int64]bool
+}
+
+func newFakeStore(ids ...int64) *fakeStore {
+	store := &fakeStore{processed: map[int64]bool{}, released: map[int64]bool{}}
+	for _, id := range ids {
+		store.events = append(store.events, models.OutboxEvent{
+			ID:    id,
+			Event: models.Event{ID: fmt.Sprintf("event-%d", id), Type: models.EventPullRequestMerged},
+		})
+	}
+	return store
+}
+
+func (f *fakeStore) ClaimBatch(_ context.Context, _ time.Time, _ time.Duration, limit int) ([]models.OutboxEvent, error) {
+	var claimed []models.OutboxEvent
+	for _, event := range f.events {
+		if !f.processed[event.ID] && len(claimed) < limit {
+			claimed = append(claimed, event)
+		}
+	}
+	return claimed, nil
+}
+
+func (f *fakeStore) MarkProcessed(_ context.Context, ids []int64) error {
+	for _, id := range ids {
+		f.processed[id] = true
+	}
+	return nil
+}
+
+func (f *fakeStore) Release(_ context.Context, ids []int64, _ time.Time) error {
+	for _, id := range ids {
+		f.released[id] = true
+	}
+	return nil
+}
+
+type recordingSink struct {
+	failOn string
+	seen   []string
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Handle(_ context.Context, event *models.Event) error {
+	if event.ID == s.failOn {
+		return errors.New("sink unavailable")
+	}
+	s.seen = append(s.seen, event.ID)
+	return nil
+}
+
+func TestDispatchBatch(t *testing.T) {
+	store := newFakeStore(1, 2, 3)
+	first, second := &recordingSink{}, &recordingSink{}
+
+	d := outbox.NewDispatcher(store, outbox.DefaultConfig())
+	d.Register(first)
+	d.Register(second)
+
+	if err := d.DispatchBatch(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, sink := range []*recordingSink{first, second} {
+		if len(sink.seen) != 3 || sink.seen[0] != "event-1" || sink.seen[2] != "event-3" {
+			t.Errorf("expected every event in order, got %v", sink.seen)
+		}
+	}
+	if len(store.processed) != 3 {
+		t.Errorf("expected all events processed, got %v", store.processed)
+	}
+}
+
+func TestDispatchBatchStopsOnFailure(t *testing.T) {
+	store := newFakeStore(1, 2, 3)
+	sink := &recordingSink{failOn: "event-2"}
+
+	d := outbox.NewDispatcher(store, outbox.DefaultConfig())
+	d.Register(sink)
+
+	if err := d.DispatchBatch(context.Background()); err == nil {
+		t.Fatal("expected sink error to be returned")
+	}
+
+	if !store.processed[1] || store.processed[2] || store.processed[3] {
+		t.Errorf("expected only the event before the failure to be processed, got %v", store.processed)
+	}
+	if !store.released[2] || !store.released[3] {
+		t.Errorf("expected failed and following events to be released for retry, got %v", store.released)
+	}
+
+	sink.failOn = ""
+	if err := d.DispatchBatch(context.Background()); err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if len(store.processed) != 3 {
+		t.Errorf("expected retry to process the remaining events, got %v", store.processed)
+	}
+}
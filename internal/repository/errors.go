@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"pr-reviewer-service/internal/service"
+)
+
+// uniqueViolation is the Postgres error code of a unique or primary key conflict.
+const uniqueViolation = "23505"
+
+// mapError translates driver errors the service layer relies on.
+func mapError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: %s", service.ErrDuplicateKey, pqErr.Constraint)
+	}
+	return err
+}
@@ -0,0 +1,171 @@
+// Package memory is an in-memory storage backend that mirrors the behavior of
+// the Postgres repositories, including key constraints and transactional
+// writes. It is meant for tests and local runs: data does not survive a restart.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/service"
+)
+
+var (
+	ErrDuplicateKey = fmt.Errorf("memory: %w", service.ErrDuplicateKey)
+	ErrForeignKey   = errors.New("memory: foreign key violation")
+)
+
+// DB holds the tables shared by the repositories of this package. Every
+// repository method runs under the DB lock, which makes it atomic; a unit of
+// work holds the lock for all of its calls.
+type DB struct {
+	mu sync.RWMutex
+
+	teams         map[string]models.TeamSettings
+	users         map[string]models.User
+	pullRequests  map[string]*pullRequestRow
+	reassignments []reassignmentRow
+	history       []models.HistoryEntry
+
+	webhooks       map[int64]models.Webhook
+	lastWebhookID  int64
+	deliveries     []*deliveryRow
+	lastDeliveryID int64
+
+	outbox       []*outboxRow
+	lastOutboxID int64
+}
+
+type pullRequestRow struct {
+	pr        models.PullRequest
+	reviewers []reviewerRow
+}
+
+type reviewerRow struct {
+	userID       string
+	assignedAt   time.Time
+	fromFallback bool
+	reviewState  string
+	reviewedAt   *time.Time
+}
+
+type reassignmentRow struct {
+	pullRequestID string
+	oldUserID     string
+	newUserID     string
+}
+
+type deliveryRow struct {
+	id            int64
+	webhookID     int64
+	eventID       string
+	eventType     string
+	payload       []byte
+	status        string
+	attempts      int
+	nextAttemptAt time.Time
+	lastError     string
+	deliveredAt   *time.Time
+}
+
+type outboxRow struct {
+	id          int64
+	eventID     string
+	payload     []byte
+	lockedUntil *time.Time
+	processedAt *time.Time
+}
+
+// lock takes the write lock unless ctx is already done. Inside a unit of work
+// the lock is already held and lock only checks ctx.
+func (db *DB) lock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !db.inTx(ctx) {
+		db.mu.Lock()
+	}
+	return nil
+}
+
+func (db *DB) unlock(ctx context.Context) {
+	if !db.inTx(ctx) {
+		db.mu.Unlock()
+	}
+}
+
+// rlock takes the read lock unless ctx is already done.
+func (db *DB) rlock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if !db.inTx(ctx) {
+		db.mu.RLock()
+	}
+	return nil
+}
+
+func (db *DB) runlock(ctx context.Context) {
+	if !db.inTx(ctx) {
+		db.mu.RUnlock()
+	}
+}
+
+func New() *DB {
+	db := &DB{}
+	db.reset()
+	return db
+}
+
+// Reset drops all data, like truncating every table.
+func (db *DB) Reset() {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.reset()
+}
+
+func (db *DB) reset() {
+	db.teams = make(map[string]models.TeamSettings)
+	db.users = make(map[string]models.User)
+	db.pullRequests = make(map[string]*pullRequestRow)
+	db.reassignments = nil
+	db.history = nil
+	db.webhooks = make(map[int64]models.Webhook)
+	db.deliveries = nil
+	db.outbox = nil
+}
+
+// insertEvent appends the event to the outbox. A nil event is a no-op.
+// The caller must hold the write lock and must not have changed anything yet,
+// so that a failure leaves the tables untouched.
+func (db *DB) insertEvent(event *models.Event) (func(), error) {
+	if event == nil {
+		return func() {}, nil
+	}
+
+	for _, row := range db.outbox {
+		if row.eventID == event.ID {
+			return nil, ErrDuplicateKey
+		}
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		db.lastOutboxID++
+		db.outbox = append(db.outbox, &outboxRow{
+			id:      db.lastOutboxID,
+			eventID: event.ID,
+			payload: payload,
+		})
+	}, nil
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+type OutboxRepository struct {
+	db *DB
+}
+
+func NewOutboxRepository(db *DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// ClaimBatch returns up to limit unprocessed events in the order they were
+// written and locks them for lease, so that other dispatchers skip them meanwhile.
+func (r *OutboxRepository) ClaimBatch(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEvent, error) {
+	if err := r.db.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.unlock(ctx)
+
+	lockedUntil := now.Add(lease)
+
+	var events []models.OutboxEvent
+	for _, row := range r.db.outbox {
+		if len(events) == limit {
+			break
+		}
+		if row.processedAt != nil || (row.lockedUntil != nil && row.lockedUntil.After(now)) {
+			continue
+		}
+
+		outboxEvent := models.OutboxEvent{ID: row.id}
+		if err := json.Unmarshal(row.payload, &outboxEvent.Event); err != nil {
+			return nil, err
+		}
+		row.lockedUntil = &lockedUntil
+		events = append(events, outboxEvent)
+	}
+
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkProcessed(ctx context.Context, ids []int64) error {
+	now := time.Now()
+	return r.update(ctx, ids, func(row *outboxRow) {
+		row.processedAt = &now
+		row.lockedUntil = nil
+	})
+}
+
+// Release makes claimed events available again after retryAt.
+func (r *OutboxRepository) Release(ctx context.Context, ids []int64, retryAt time.Time) error {
+	return r.update(ctx, ids, func(row *outboxRow) {
+		row.lockedUntil = &retryAt
+	})
+}
+
+func (r *OutboxRepository) update(ctx context.Context, ids []int64, apply func(row *outboxRow)) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	wanted := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	for _, row := range r.db.outbox {
+		if wanted[row.id] {
+			apply(row)
+		}
+	}
+	return nil
+}
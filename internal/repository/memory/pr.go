@@ -0,0 +1,791 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sort"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+type PRRepository struct {
+	db *DB
+}
+
+func NewPRRepository(db *DB) *PRRepository {
+	return &PRRepository{db: db}
+}
+
+// Create inserts the pull request with its reviewers, flagging the ones listed
+// in FallbackReviewers, and, when event is not nil, records the event in the
+// outbox atomically.
+func (r *PRRepository) Create(ctx context.Context, pr *models.PullRequest, event *models.Event) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	if _, ok := r.db.pullRequests[pr.PullRequestID]; ok {
+		return ErrDuplicateKey
+	}
+	if _, ok := r.db.users[pr.AuthorID]; !ok {
+		return ErrForeignKey
+	}
+
+	now := time.Now()
+	reviewers := make([]reviewerRow, 0, len(pr.AssignedReviewers))
+	for _, reviewerID := range pr.AssignedReviewers {
+		if _, ok := r.db.users[reviewerID]; !ok {
+			return ErrForeignKey
+		}
+		if hasReviewer(reviewers, reviewerID) {
+			return ErrDuplicateKey
+		}
+		reviewers = append(reviewers, reviewerRow{
+			userID:       reviewerID,
+			assignedAt:   now,
+			fromFallback: slices.Contains(pr.FallbackReviewers, reviewerID),
+			reviewState:  models.ReviewPending,
+		})
+	}
+
+	commitEvent, err := r.db.insertEvent(event)
+	if err != nil {
+		return err
+	}
+
+	createdAt := now
+	if pr.CreatedAt != nil {
+		createdAt = *pr.CreatedAt
+	}
+
+	r.db.pullRequests[pr.PullRequestID] = &pullRequestRow{
+		pr: models.PullRequest{
+			CreatedAt:         &createdAt,
+			PullRequestID:     pr.PullRequestID,
+			PullRequestName:   pr.PullRequestName,
+			AuthorID:          pr.AuthorID,
+			Status:            pr.Status,
+			RequiredReviewers: pr.RequiredReviewers,
+		},
+		reviewers: reviewers,
+	}
+	commitEvent()
+
+	pr.CreatedAt = &createdAt
+	return nil
+}
+
+func (r *PRRepository) GetByID(ctx context.Context, prID string) (*models.PullRequest, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	row, ok := r.db.pullRequests[prID]
+	if !ok {
+		return nil, nil
+	}
+
+	pr := r.db.pullRequestOf(row, []string{})
+	return &pr, nil
+}
+
+// GetByIDs returns the pull requests that exist among prIDs, ordered by
+// pull_request_id.
+func (r *PRRepository) GetByIDs(ctx context.Context, prIDs []string) ([]models.PullRequest, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	var prs []models.PullRequest
+	for _, prID := range prIDs {
+		if row, ok := r.db.pullRequests[prID]; ok {
+			prs = append(prs, r.db.pullRequestOf(row, []string{}))
+		}
+	}
+
+	sort.Slice(prs, func(i, j int) bool {
+		return prs[i].PullRequestID < prs[j].PullRequestID
+	})
+	return slices.CompactFunc(prs, func(a, b models.PullRequest) bool {
+		return a.PullRequestID == b.PullRequestID
+	}), nil
+}
+
+func (r *PRRepository) Exists(ctx context.Context, prID string) (bool, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return false, err
+	}
+	defer r.db.runlock(ctx)
+
+	_, ok := r.db.pullRequests[prID]
+	return ok, nil
+}
+
+// Lock only checks that the pull request exists: a unit of work already holds
+// the DB lock for its whole duration.
+func (r *PRRepository) Lock(ctx context.Context, prID string) (bool, error) {
+	return r.Exists(ctx, prID)
+}
+
+// LockAll is a no-op for the same reason as Lock.
+func (r *PRRepository) LockAll(context.Context, []string) error {
+	return nil
+}
+
+// UpdateStatus sets the status, stamping merged_at with changedAt for MERGED,
+// and records the event in the outbox atomically.
+func (r *PRRepository) UpdateStatus(ctx context.Context, prID, status string, changedAt time.Time, event *models.Event) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	commitEvent, err := r.db.insertEvent(event)
+	if err != nil {
+		return err
+	}
+
+	if row, ok := r.db.pullRequests[prID]; ok {
+		row.pr.Status = status
+		row.pr.MergedAt = nil
+		if status == models.StatusMerged {
+			mergedAt := changedAt
+			row.pr.MergedAt = &mergedAt
+		}
+	}
+	commitEvent()
+
+	return nil
+}
+
+// ReplaceReviewer swaps a reviewer, logs the reassignment and records the event
+// in the outbox atomically. fromFallback flags the new reviewer as borrowed from
+// a fallback team.
+func (r *PRRepository) ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string, fromFallback bool, event *models.Event) error {
+	replacement := models.ReviewerReplacement{
+		PullRequestID: prID,
+		OldUserID:     oldReviewerID,
+		NewUserID:     newReviewerID,
+		FromFallback:  fromFallback,
+	}
+	return r.ReplaceReviewers(ctx, []models.ReviewerReplacement{replacement}, []*models.Event{event})
+}
+
+// ReplaceReviewers applies a batch of reviewer swaps, logs the reassignments
+// and records the events in the outbox atomically. It returns sql.ErrNoRows
+// when an old reviewer is no longer assigned.
+func (r *PRRepository) ReplaceReviewers(ctx context.Context, replacements []models.ReviewerReplacement, events []*models.Event) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	staged, err := r.db.stageReplacements(replacements)
+	if err != nil {
+		return err
+	}
+
+	commits := make([]func(), 0, len(events))
+	for _, event := range events {
+		commitEvent, eventErr := r.db.insertEvent(event)
+		if eventErr != nil {
+			return eventErr
+		}
+		commits = append(commits, commitEvent)
+	}
+
+	r.db.applyReplacements(staged, replacements)
+	for _, commitEvent := range commits {
+		commitEvent()
+	}
+
+	return nil
+}
+
+// AddReviewers assigns more reviewers to the pull request, flagging the ones
+// listed in fallbackIDs, and records the event in the outbox atomically.
+func (r *PRRepository) AddReviewers(ctx context.Context, prID string, reviewerIDs, fallbackIDs []string, event *models.Event) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	row, ok := r.db.pullRequests[prID]
+	if !ok {
+		return ErrForeignKey
+	}
+
+	now := time.Now()
+	reviewers := slices.Clone(row.reviewers)
+	for _, reviewerID := range reviewerIDs {
+		if _, ok := r.db.users[reviewerID]; !ok {
+			return ErrForeignKey
+		}
+		if hasReviewer(reviewers, reviewerID) {
+			return ErrDuplicateKey
+		}
+		reviewers = append(reviewers, reviewerRow{
+			userID:       reviewerID,
+			assignedAt:   now,
+			fromFallback: slices.Contains(fallbackIDs, reviewerID),
+			reviewState:  models.ReviewPending,
+		})
+	}
+
+	commitEvent, err := r.db.insertEvent(event)
+	if err != nil {
+		return err
+	}
+
+	row.reviewers = reviewers
+	commitEvent()
+
+	return nil
+}
+
+// RemoveReviewer unassigns the reviewer and records the event in the outbox
+// atomically.
+func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, userID string, event *models.Event) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	row, ok := r.db.pullRequests[prID]
+	if !ok || !hasReviewer(row.reviewers, userID) {
+		return sql.ErrNoRows
+	}
+
+	commitEvent, err := r.db.insertEvent(event)
+	if err != nil {
+		return err
+	}
+
+	row.reviewers = slices.DeleteFunc(slices.Clone(row.reviewers), func(reviewer reviewerRow) bool {
+		return reviewer.userID == userID
+	})
+	commitEvent()
+
+	return nil
+}
+
+// SubmitReview records the reviewer's decision, stamped with reviewedAt, and
+// the event in the outbox atomically.
+func (r *PRRepository) SubmitReview(ctx context.Context, prID, userID, state string, reviewedAt time.Time, event *models.Event) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	row, ok := r.db.pullRequests[prID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	i := slices.IndexFunc(row.reviewers, func(reviewer reviewerRow) bool {
+		return reviewer.userID == userID
+	})
+	if i < 0 {
+		return sql.ErrNoRows
+	}
+
+	commitEvent, err := r.db.insertEvent(event)
+	if err != nil {
+		return err
+	}
+
+	reviewers := slices.Clone(row.reviewers)
+	reviewers[i].reviewState = state
+	reviewers[i].reviewedAt = &reviewedAt
+	row.reviewers = reviewers
+	commitEvent()
+
+	return nil
+}
+
+func (r *PRRepository) UpdateRequiredReviewers(ctx context.Context, prID string, required int) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	row, ok := r.db.pullRequests[prID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	row.pr.RequiredReviewers = required
+	return nil
+}
+
+func (r *PRRepository) IsReviewerAssigned(ctx context.Context, prID, userID string) (bool, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return false, err
+	}
+	defer r.db.runlock(ctx)
+
+	row, ok := r.db.pullRequests[prID]
+	return ok && hasReviewer(row.reviewers, userID), nil
+}
+
+func (r *PRRepository) GetByReviewer(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	var rows []*pullRequestRow
+	for _, row := range r.db.pullRequests {
+		if hasReviewer(row.reviewers, userID) {
+			rows = append(rows, row)
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].pr.CreatedAt.After(*rows[j].pr.CreatedAt)
+	})
+
+	var prs []models.PullRequestShort
+	for _, row := range rows {
+		prs = append(prs, models.PullRequestShort{
+			PullRequestID:   row.pr.PullRequestID,
+			PullRequestName: row.pr.PullRequestName,
+			AuthorID:        row.pr.AuthorID,
+			Status:          row.pr.Status,
+			ReviewState:     reviewerOf(row.reviewers, userID).reviewState,
+		})
+	}
+
+	return prs, nil
+}
+
+func (r *PRRepository) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	wanted := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		wanted[userID] = true
+	}
+
+	counts := make(map[string]int, len(userIDs))
+	for _, row := range r.db.pullRequests {
+		if row.pr.Status != models.StatusOpen {
+			continue
+		}
+		for _, reviewer := range row.reviewers {
+			if wanted[reviewer.userID] {
+				counts[reviewer.userID]++
+			}
+		}
+	}
+
+	return counts, nil
+}
+
+func (r *PRRepository) List(ctx context.Context, filter *models.PullRequestFilter) ([]models.PullRequest, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	byID := filter.SortBy == models.SortByPullRequestID
+	desc := filter.Order == models.OrderDesc
+
+	// less orders pull requests as the SQL ORDER BY of the Postgres repository does
+	less := func(a, b *models.PullRequest) bool {
+		if !byID && !a.CreatedAt.Equal(*b.CreatedAt) {
+			return a.CreatedAt.Before(*b.CreatedAt) != desc
+		}
+		return (a.PullRequestID < b.PullRequestID) != desc
+	}
+
+	var cursor *models.PullRequest
+	if filter.After != nil {
+		createdAt := filter.After.CreatedAt
+		cursor = &models.PullRequest{CreatedAt: &createdAt, PullRequestID: filter.After.PullRequestID}
+	}
+
+	var matched []*pullRequestRow
+	for _, row := range r.db.pullRequests {
+		pr := &row.pr
+
+		if filter.Status != "" && pr.Status != filter.Status {
+			continue
+		}
+		if filter.AuthorID != "" && pr.AuthorID != filter.AuthorID {
+			continue
+		}
+		if filter.TeamName != "" && r.db.users[pr.AuthorID].TeamName != filter.TeamName {
+			continue
+		}
+		if filter.ReviewerID != "" && !hasReviewer(row.reviewers, filter.ReviewerID) {
+			continue
+		}
+		if filter.CreatedFrom != nil && pr.CreatedAt.Before(*filter.CreatedFrom) {
+			continue
+		}
+		if filter.CreatedTo != nil && !pr.CreatedAt.Before(*filter.CreatedTo) {
+			continue
+		}
+		if cursor != nil && !less(cursor, pr) {
+			continue
+		}
+		matched = append(matched, row)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return less(&matched[i].pr, &matched[j].pr)
+	})
+	if len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+
+	var prs []models.PullRequest
+	for _, row := range matched {
+		prs = append(prs, r.db.pullRequestOf(row, []string{}))
+	}
+
+	return prs, nil
+}
+
+// GetReviewerStats aggregates review load per user, optionally limited to one team.
+// Reassigned-away and removed reviews are added to the total from the
+// reassignments log and the history.
+func (r *PRRepository) GetReviewerStats(ctx context.Context, teamName string) ([]models.ReviewerStats, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	type mergeTimes struct {
+		sum   float64
+		count int
+	}
+
+	statsByUser := make(map[string]*models.ReviewerStats)
+	merges := make(map[string]*mergeTimes)
+	var stats []*models.ReviewerStats
+
+	for _, user := range r.db.users {
+		if teamName != "" && user.TeamName != teamName {
+			continue
+		}
+		st := &models.ReviewerStats{UserID: user.UserID, Username: user.Username, TeamName: user.TeamName}
+		statsByUser[user.UserID] = st
+		merges[user.UserID] = &mergeTimes{}
+		stats = append(stats, st)
+	}
+
+	for _, row := range r.db.pullRequests {
+		for _, reviewer := range row.reviewers {
+			st, ok := statsByUser[reviewer.userID]
+			if !ok {
+				continue
+			}
+			st.TotalAssignments++
+			switch row.pr.Status {
+			case models.StatusOpen:
+				st.OpenAssignments++
+			case models.StatusMerged:
+				merge := merges[reviewer.userID]
+				merge.sum += row.pr.MergedAt.Sub(reviewer.assignedAt).Seconds()
+				merge.count++
+			}
+		}
+	}
+
+	for _, reassignment := range r.db.reassignments {
+		if st, ok := statsByUser[reassignment.oldUserID]; ok {
+			st.TotalAssignments++
+			st.ReassignedAway++
+		}
+	}
+
+	for _, entry := range r.db.history {
+		if st, ok := statsByUser[entry.UserID]; ok && entry.Action == models.HistoryReviewerRemoved {
+			st.TotalAssignments++
+		}
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TeamName != stats[j].TeamName {
+			return stats[i].TeamName < stats[j].TeamName
+		}
+		return stats[i].UserID < stats[j].UserID
+	})
+
+	result := make([]models.ReviewerStats, 0, len(stats))
+	for _, st := range stats {
+		if merge := merges[st.UserID]; merge.count > 0 {
+			avg := merge.sum / float64(merge.count)
+			st.AvgTimeToMergeSeconds = &avg
+		}
+		result = append(result, *st)
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// GetOpenReviewsOf returns every reviewer assignment on the OPEN pull requests
+// reviewed by any of the given users, including assignments of other reviewers.
+func (r *PRRepository) GetOpenReviewsOf(ctx context.Context, userIDs []string) ([]models.OpenReview, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	var reviews []models.OpenReview
+	for _, row := range r.db.pullRequests {
+		if row.pr.Status != models.StatusOpen || !hasAnyReviewer(row.reviewers, userIDs) {
+			continue
+		}
+		for _, reviewer := range row.reviewers {
+			reviews = append(reviews, models.OpenReview{
+				PullRequestID: row.pr.PullRequestID,
+				AuthorID:      row.pr.AuthorID,
+				ReviewerID:    reviewer.userID,
+			})
+		}
+	}
+
+	sort.Slice(reviews, func(i, j int) bool {
+		if reviews[i].PullRequestID != reviews[j].PullRequestID {
+			return reviews[i].PullRequestID < reviews[j].PullRequestID
+		}
+		return reviews[i].ReviewerID < reviews[j].ReviewerID
+	})
+
+	return reviews, nil
+}
+
+// HasOpenPullRequests reports whether any of the users authors or reviews an
+// OPEN pull request.
+func (r *PRRepository) HasOpenPullRequests(ctx context.Context, userIDs []string) (bool, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return false, err
+	}
+	defer r.db.runlock(ctx)
+
+	for _, row := range r.db.pullRequests {
+		if row.pr.Status != models.StatusOpen {
+			continue
+		}
+		if slices.Contains(userIDs, row.pr.AuthorID) || hasAnyReviewer(row.reviewers, userIDs) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetUnderstaffed returns the OPEN pull requests with fewer reviewers than
+// required, oldest first; a non-empty teamName keeps the ones of its authors.
+func (r *PRRepository) GetUnderstaffed(ctx context.Context, teamName string) ([]string, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	var prs []models.PullRequest
+	for _, row := range r.db.pullRequests {
+		if row.pr.Status != models.StatusOpen || len(row.reviewers) >= row.pr.RequiredReviewers {
+			continue
+		}
+		if teamName != "" && r.db.users[row.pr.AuthorID].TeamName != teamName {
+			continue
+		}
+		prs = append(prs, row.pr)
+	}
+
+	sort.Slice(prs, func(i, j int) bool {
+		if !prs[i].CreatedAt.Equal(*prs[j].CreatedAt) {
+			return prs[i].CreatedAt.Before(*prs[j].CreatedAt)
+		}
+		return prs[i].PullRequestID < prs[j].PullRequestID
+	})
+
+	var prIDs []string
+	for _, pr := range prs {
+		prIDs = append(prIDs, pr.PullRequestID)
+	}
+	return prIDs, nil
+}
+
+// AddHistory appends entries to the history of their pull requests in the
+// given order.
+func (r *PRRepository) AddHistory(ctx context.Context, entries []models.HistoryEntry) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	for _, entry := range entries {
+		if _, ok := r.db.pullRequests[entry.PullRequestID]; !ok {
+			return ErrForeignKey
+		}
+	}
+
+	r.db.history = append(r.db.history, entries...)
+	return nil
+}
+
+// GetHistory returns the history of the pull request, oldest first.
+func (r *PRRepository) GetHistory(ctx context.Context, prID string) ([]models.HistoryEntry, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	var entries []models.HistoryEntry
+	for _, entry := range r.db.history {
+		if entry.PullRequestID == prID {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// stageReplacements computes the reviewer lists that result from removing every
+// old reviewer and then adding every new one, validating keys on the way.
+// Nothing is changed until the result is passed to applyReplacements.
+func (db *DB) stageReplacements(replacements []models.ReviewerReplacement) (map[string][]reviewerRow, error) {
+	staged := make(map[string][]reviewerRow)
+
+	for _, replacement := range replacements {
+		row, ok := db.pullRequests[replacement.PullRequestID]
+		if !ok {
+			return nil, ErrForeignKey
+		}
+		if _, ok := db.users[replacement.OldUserID]; !ok {
+			return nil, ErrForeignKey
+		}
+		if _, ok := db.users[replacement.NewUserID]; !ok {
+			return nil, ErrForeignKey
+		}
+		if _, ok := staged[replacement.PullRequestID]; !ok {
+			staged[replacement.PullRequestID] = append([]reviewerRow(nil), row.reviewers...)
+		}
+	}
+
+	for _, replacement := range replacements {
+		reviewers := staged[replacement.PullRequestID]
+		if !hasReviewer(reviewers, replacement.OldUserID) {
+			return nil, sql.ErrNoRows
+		}
+		staged[replacement.PullRequestID] = slices.DeleteFunc(reviewers, func(reviewer reviewerRow) bool {
+			return reviewer.userID == replacement.OldUserID
+		})
+	}
+
+	now := time.Now()
+	for _, replacement := range replacements {
+		reviewers := staged[replacement.PullRequestID]
+		if hasReviewer(reviewers, replacement.NewUserID) {
+			return nil, ErrDuplicateKey
+		}
+		staged[replacement.PullRequestID] = append(reviewers, reviewerRow{
+			userID:       replacement.NewUserID,
+			assignedAt:   now,
+			fromFallback: replacement.FromFallback,
+			reviewState:  models.ReviewPending,
+		})
+	}
+
+	return staged, nil
+}
+
+func (db *DB) applyReplacements(staged map[string][]reviewerRow, replacements []models.ReviewerReplacement) {
+	for prID, reviewers := range staged {
+		db.pullRequests[prID].reviewers = reviewers
+	}
+
+	for _, replacement := range replacements {
+		db.reassignments = append(db.reassignments, reassignmentRow{
+			pullRequestID: replacement.PullRequestID,
+			oldUserID:     replacement.OldUserID,
+			newUserID:     replacement.NewUserID,
+		})
+	}
+}
+
+// pullRequestOf copies the row with its reviewers ordered by assignment time,
+// appending reviewer ids to assigned.
+func (db *DB) pullRequestOf(row *pullRequestRow, assigned []string) models.PullRequest {
+	pr := row.pr
+	pr.CreatedAt = copyTime(pr.CreatedAt)
+	pr.MergedAt = copyTime(pr.MergedAt)
+	pr.AssignedReviewers = assigned
+	pr.FallbackReviewers = nil
+	pr.Reviewers = nil
+
+	reviewers := append([]reviewerRow(nil), row.reviewers...)
+	sort.Slice(reviewers, func(i, j int) bool {
+		if !reviewers[i].assignedAt.Equal(reviewers[j].assignedAt) {
+			return reviewers[i].assignedAt.Before(reviewers[j].assignedAt)
+		}
+		return reviewers[i].userID < reviewers[j].userID
+	})
+
+	for _, reviewer := range reviewers {
+		assignedAt := reviewer.assignedAt
+		pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer.userID)
+		if reviewer.fromFallback {
+			pr.FallbackReviewers = append(pr.FallbackReviewers, reviewer.userID)
+		}
+		pr.Reviewers = append(pr.Reviewers, models.Reviewer{
+			AssignedAt:   &assignedAt,
+			UserID:       reviewer.userID,
+			Username:     db.users[reviewer.userID].Username,
+			ReviewedAt:   copyTime(reviewer.reviewedAt),
+			FromFallback: reviewer.fromFallback,
+			ReviewState:  reviewer.reviewState,
+		})
+	}
+
+	return pr
+}
+
+func reviewerOf(reviewers []reviewerRow, userID string) reviewerRow {
+	for _, reviewer := range reviewers {
+		if reviewer.userID == userID {
+			return reviewer
+		}
+	}
+	return reviewerRow{}
+}
+
+func hasReviewer(reviewers []reviewerRow, userID string) bool {
+	for _, reviewer := range reviewers {
+		if reviewer.userID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyReviewer(reviewers []reviewerRow, userIDs []string) bool {
+	for _, userID := range userIDs {
+		if hasReviewer(reviewers, userID) {
+			return true
+		}
+	}
+	return false
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
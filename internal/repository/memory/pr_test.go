@@ -0,0 +1,93 @@
+package memory_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository/memory"
+)
+
+func setup(t *testing.T) (*memory.DB, *memory.PRRepository) {
+	t.Helper()
+
+	db := memory.New()
+	team := &models.Team{TeamName: "backend", ReviewerStrategy: models.StrategyRandom, ReviewersCount: 2}
+	if err := memory.NewTeamRepository(db).Create(context.Background(), team); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+
+	users := memory.NewUserRepository(db)
+	for _, userID := range []string{"u1", "u2", "u3"} {
+		if err := users.Create(context.Background(), &models.User{UserID: userID, Username: userID, TeamName: "backend", IsActive: true}); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+
+	return db, memory.NewPRRepository(db)
+}
+
+func TestConcurrentCreate(t *testing.T) {
+	_, prs := setup(t)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pr := &models.PullRequest{
+				PullRequestID:     "pr-1",
+				PullRequestName:   "Race",
+				AuthorID:          "u1",
+				Status:            models.StatusOpen,
+				AssignedReviewers: []string{"u2", "u3"},
+			}
+			if err := prs.Create(context.Background(), pr, nil); err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if created != 1 {
+		t.Errorf("expected exactly one successful create, got %d", created)
+	}
+}
+
+func TestCreateIsAtomic(t *testing.T) {
+	db, prs := setup(t)
+
+	event := &models.Event{ID: "event-1", Type: models.EventReviewersAssigned, TeamName: "backend", PullRequestID: "pr-1"}
+	pr := &models.PullRequest{PullRequestID: "pr-1", PullRequestName: "First", AuthorID: "u1", Status: models.StatusOpen}
+	if err := prs.Create(context.Background(), pr, event); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// the duplicate event id fails the second create, which must leave no pull request behind
+	pr = &models.PullRequest{PullRequestID: "pr-2", PullRequestName: "Second", AuthorID: "u1", Status: models.StatusOpen}
+	if err := prs.Create(context.Background(), pr, event); err != memory.ErrDuplicateKey {
+		t.Fatalf("expected ErrDuplicateKey, got %v", err)
+	}
+
+	exists, err := prs.Exists(context.Background(), "pr-2")
+	if err != nil {
+		t.Fatalf("exists: %v", err)
+	}
+	if exists {
+		t.Error("expected pr-2 not to be created")
+	}
+
+	events, err := memory.NewOutboxRepository(db).ClaimBatch(context.Background(), time.Now(), time.Minute, 10)
+	if err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+	if len(events) != 1 || events[0].Event.ID != "event-1" {
+		t.Errorf("expected only event-1 in the outbox, got %v", events)
+	}
+}
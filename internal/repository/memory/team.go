@@ -0,0 +1,250 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sort"
+
+	"pr-reviewer-service/internal/models"
+)
+
+type TeamRepository struct {
+	db *DB
+}
+
+func NewTeamRepository(db *DB) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	if _, ok := r.db.teams[team.TeamName]; ok {
+		return ErrDuplicateKey
+	}
+
+	r.db.teams[team.TeamName] = models.TeamSettings{
+		TeamName:         team.TeamName,
+		ReviewerStrategy: team.ReviewerStrategy,
+		ReviewersCount:   team.ReviewersCount,
+	}
+	return nil
+}
+
+func (r *TeamRepository) Exists(ctx context.Context, teamName string) (bool, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return false, err
+	}
+	defer r.db.runlock(ctx)
+
+	_, ok := r.db.teams[teamName]
+	return ok, nil
+}
+
+func (r *TeamRepository) Get(ctx context.Context, teamName string) (*models.Team, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	settings, ok := r.db.teams[teamName]
+	if !ok {
+		return nil, nil
+	}
+
+	var members []models.TeamMember
+	for _, user := range r.db.usersOf(teamName, func(models.User) bool { return true }) {
+		members = append(members, models.TeamMember{
+			UserID:   user.UserID,
+			Username: user.Username,
+			IsActive: user.IsActive,
+		})
+	}
+
+	team := &models.Team{
+		TeamName:         teamName,
+		ReviewerStrategy: settings.ReviewerStrategy,
+		ReviewersCount:   settings.ReviewersCount,
+		Members:          members,
+	}
+
+	return team, nil
+}
+
+func (r *TeamRepository) List(ctx context.Context, filter *models.TeamFilter) ([]models.TeamSummary, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	var teams []models.TeamSummary
+	for _, settings := range r.db.teams {
+		if settings.TeamName <= filter.After {
+			continue
+		}
+
+		team := models.TeamSummary{
+			TeamName:         settings.TeamName,
+			ReviewerStrategy: settings.ReviewerStrategy,
+			ReviewersCount:   settings.ReviewersCount,
+		}
+		for _, user := range r.db.usersOf(settings.TeamName, func(models.User) bool { return true }) {
+			team.MembersCount++
+			if user.IsActive {
+				team.ActiveMembersCount++
+			}
+		}
+		teams = append(teams, team)
+	}
+
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].TeamName < teams[j].TeamName
+	})
+	if len(teams) > filter.Limit {
+		teams = teams[:filter.Limit]
+	}
+
+	return teams, nil
+}
+
+func (r *TeamRepository) GetSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	settings, ok := r.db.teams[teamName]
+	if !ok {
+		return nil, nil
+	}
+	settings.FallbackTeams = append([]string{}, settings.FallbackTeams...)
+	return &settings, nil
+}
+
+// GetFallingBackTo returns the names of the teams that list teamName among
+// their fallback teams.
+func (r *TeamRepository) GetFallingBackTo(ctx context.Context, teamName string) ([]string, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	var teamNames []string
+	for name, settings := range r.db.teams {
+		if slices.Contains(settings.FallbackTeams, teamName) {
+			teamNames = append(teamNames, name)
+		}
+	}
+	sort.Strings(teamNames)
+	return teamNames, nil
+}
+
+// UpdateSettings stores the settings, replacing the fallback teams as a whole.
+func (r *TeamRepository) UpdateSettings(ctx context.Context, settings *models.TeamSettings) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	if _, ok := r.db.teams[settings.TeamName]; !ok {
+		return nil
+	}
+	for i, fallbackTeam := range settings.FallbackTeams {
+		if _, ok := r.db.teams[fallbackTeam]; !ok || fallbackTeam == settings.TeamName {
+			return ErrForeignKey
+		}
+		if slices.Contains(settings.FallbackTeams[:i], fallbackTeam) {
+			return ErrDuplicateKey
+		}
+	}
+
+	stored := *settings
+	stored.FallbackTeams = slices.Clone(settings.FallbackTeams)
+	r.db.teams[settings.TeamName] = stored
+	return nil
+}
+
+// Rename moves the team, its members, webhooks and fallback links to newName.
+func (r *TeamRepository) Rename(ctx context.Context, teamName, newName string) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	settings, ok := r.db.teams[teamName]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if _, ok := r.db.teams[newName]; ok {
+		return ErrDuplicateKey
+	}
+
+	settings.TeamName = newName
+	r.db.teams[newName] = settings
+	delete(r.db.teams, teamName)
+
+	r.db.moveTeam(teamName, newName)
+	return nil
+}
+
+// Delete removes the team with its webhooks and fallback links, also the ones
+// of teams falling back to it. Members stay as users without a team, keeping
+// their review history.
+func (r *TeamRepository) Delete(ctx context.Context, teamName string) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	if _, ok := r.db.teams[teamName]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.db.teams, teamName)
+
+	r.db.moveTeam(teamName, "")
+	for id, webhook := range r.db.webhooks {
+		if webhook.TeamName == "" {
+			r.db.deleteWebhook(id)
+		}
+	}
+	return nil
+}
+
+// moveTeam points the members, webhooks and fallback links of a team at
+// another team name; an empty name drops the fallback links.
+func (db *DB) moveTeam(teamName, newName string) {
+	for name, settings := range db.teams {
+		if !slices.Contains(settings.FallbackTeams, teamName) {
+			continue
+		}
+
+		var fallbackTeams []string
+		for _, fallbackTeam := range settings.FallbackTeams {
+			if fallbackTeam == teamName {
+				fallbackTeam = newName
+			}
+			if fallbackTeam != "" {
+				fallbackTeams = append(fallbackTeams, fallbackTeam)
+			}
+		}
+		settings.FallbackTeams = fallbackTeams
+		db.teams[name] = settings
+	}
+
+	for userID, user := range db.users {
+		if user.TeamName == teamName {
+			user.TeamName = newName
+			db.users[userID] = user
+		}
+	}
+	for id, webhook := range db.webhooks {
+		if webhook.TeamName == teamName {
+			webhook.TeamName = newName
+			db.webhooks[id] = webhook
+		}
+	}
+}
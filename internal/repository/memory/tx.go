@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"maps"
+
+	"pr-reviewer-service/internal/models"
+)
+
+type txKey struct{}
+
+// TxManager runs units of work under the DB write lock, so they are isolated
+// from every other call, and restores the tables when a unit of work fails.
+type TxManager struct {
+	db *DB
+}
+
+func NewTxManager(db *DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTx calls fn with a context that carries the unit of work. Nested calls
+// join the outer one.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.db.inTx(ctx) {
+		return fn(ctx)
+	}
+
+	if err := m.db.lock(ctx); err != nil {
+		return err
+	}
+	defer m.db.mu.Unlock()
+
+	snapshot := m.db.clone()
+	if err := fn(context.WithValue(ctx, txKey{}, m.db)); err != nil {
+		m.db.restore(snapshot)
+		return err
+	}
+	return nil
+}
+
+func (db *DB) inTx(ctx context.Context) bool {
+	return ctx.Value(txKey{}) == db
+}
+
+// clone copies the tables, leaving the lock out.
+func (db *DB) clone() *DB {
+	c := &DB{
+		teams:          maps.Clone(db.teams),
+		users:          maps.Clone(db.users),
+		pullRequests:   make(map[string]*pullRequestRow, len(db.pullRequests)),
+		reassignments:  append([]reassignmentRow(nil), db.reassignments...),
+		history:        append([]models.HistoryEntry(nil), db.history...),
+		webhooks:       maps.Clone(db.webhooks),
+		lastWebhookID:  db.lastWebhookID,
+		deliveries:     make([]*deliveryRow, 0, len(db.deliveries)),
+		lastDeliveryID: db.lastDeliveryID,
+		outbox:         make([]*outboxRow, 0, len(db.outbox)),
+		lastOutboxID:   db.lastOutboxID,
+	}
+
+	for id, row := range db.pullRequests {
+		c.pullRequests[id] = &pullRequestRow{
+			pr:        row.pr,
+			reviewers: append([]reviewerRow(nil), row.reviewers...),
+		}
+	}
+	for _, row := range db.deliveries {
+		delivery := *row
+		c.deliveries = append(c.deliveries, &delivery)
+	}
+	for _, row := range db.outbox {
+		event := *row
+		c.outbox = append(c.outbox, &event)
+	}
+
+	return c
+}
+
+func (db *DB) restore(snapshot *DB) {
+	db.teams = snapshot.teams
+	db.users = snapshot.users
+	db.pullRequests = snapshot.pullRequests
+	db.reassignments = snapshot.reassignments
+	db.history = snapshot.history
+	db.webhooks = snapshot.webhooks
+	db.lastWebhookID = snapshot.lastWebhookID
+	db.deliveries = snapshot.deliveries
+	db.lastDeliveryID = snapshot.lastDeliveryID
+	db.outbox = snapshot.outbox
+	db.lastOutboxID = snapshot.lastOutboxID
+}
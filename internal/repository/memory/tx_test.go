@@ -0,0 +1,69 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/repository/memory"
+)
+
+func TestWithinTxRollsBack(t *testing.T) {
+	db, _ := setup(t)
+	teams := memory.NewTeamRepository(db)
+	users := memory.NewUserRepository(db)
+
+	// the second user references a missing team, which must undo the whole unit of work
+	err := memory.NewTxManager(db).WithinTx(context.Background(), func(ctx context.Context) error {
+		if err := teams.Create(ctx, &models.Team{TeamName: "frontend", ReviewerStrategy: models.StrategyRandom, ReviewersCount: 2}); err != nil {
+			return err
+		}
+		if err := users.Create(ctx, &models.User{UserID: "u4", Username: "u4", TeamName: "frontend", IsActive: true}); err != nil {
+			return err
+		}
+		return users.Create(ctx, &models.User{UserID: "u5", Username: "u5", TeamName: "missing", IsActive: true})
+	})
+	if !errors.Is(err, memory.ErrForeignKey) {
+		t.Fatalf("expected ErrForeignKey, got %v", err)
+	}
+
+	exists, err := teams.Exists(context.Background(), "frontend")
+	if err != nil {
+		t.Fatalf("exists: %v", err)
+	}
+	if exists {
+		t.Error("expected frontend not to be created")
+	}
+
+	user, err := users.GetByID(context.Background(), "u4")
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	if user != nil {
+		t.Errorf("expected u4 not to be created, got %v", user)
+	}
+}
+
+func TestWithinTxJoinsOuterUnit(t *testing.T) {
+	db, _ := setup(t)
+	txManager := memory.NewTxManager(db)
+	users := memory.NewUserRepository(db)
+
+	err := txManager.WithinTx(context.Background(), func(ctx context.Context) error {
+		return txManager.WithinTx(ctx, func(ctx context.Context) error {
+			return users.UpdateIsActive(ctx, "u2", false)
+		})
+	})
+	if err != nil {
+		t.Fatalf("within tx: %v", err)
+	}
+
+	user, err := users.GetByID(context.Background(), "u2")
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	if user.IsActive {
+		t.Error("expected u2 to be inactive")
+	}
+}
@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sort"
+
+	"pr-reviewer-service/internal/models"
+)
+
+type UserRepository struct {
+	db *DB
+}
+
+func NewUserRepository(db *DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Create inserts the user or updates an existing one with the same user_id.
+// An empty team name leaves the user without a team.
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	if !r.db.teamExists(user.TeamName) {
+		return ErrForeignKey
+	}
+
+	r.db.users[user.UserID] = *user
+	return nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, userID string) (*models.User, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	user, ok := r.db.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+// GetByIDs returns the users that exist among userIDs.
+func (r *UserRepository) GetByIDs(ctx context.Context, userIDs []string) ([]models.User, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	var users []models.User
+	for userID, user := range r.db.users {
+		if slices.Contains(userIDs, userID) {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func (r *UserRepository) GetByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	return r.db.usersOf(teamName, func(models.User) bool { return true }), nil
+}
+
+func (r *UserRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	user, ok := r.db.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	user.IsActive = isActive
+	r.db.users[userID] = user
+	return nil
+}
+
+// UpdateTeam moves the user to another team; an empty team name removes the
+// user from their team.
+func (r *UserRepository) UpdateTeam(ctx context.Context, userID, teamName string) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	user, ok := r.db.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if !r.db.teamExists(teamName) {
+		return ErrForeignKey
+	}
+
+	user.TeamName = teamName
+	r.db.users[userID] = user
+	return nil
+}
+
+func (r *UserRepository) GetActiveTeamMembers(ctx context.Context, teamName, excludeUserID string) ([]models.User, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	return r.db.usersOf(teamName, func(user models.User) bool {
+		return user.IsActive && user.UserID != excludeUserID
+	}), nil
+}
+
+// GetActiveMembersOfTeams returns the active members of any of the teams.
+func (r *UserRepository) GetActiveMembersOfTeams(ctx context.Context, teamNames []string) ([]models.User, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	var users []models.User
+	for _, teamName := range slices.Compact(slices.Sorted(slices.Values(teamNames))) {
+		users = append(users, r.db.usersOf(teamName, func(user models.User) bool {
+			return user.IsActive
+		})...)
+	}
+	return users, nil
+}
+
+func (r *UserRepository) List(ctx context.Context, filter *models.UserFilter) ([]models.User, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	var users []models.User
+	for _, user := range r.db.users {
+		if filter.TeamName != "" && user.TeamName != filter.TeamName {
+			continue
+		}
+		if filter.IsActive != nil && user.IsActive != *filter.IsActive {
+			continue
+		}
+		if user.UserID <= filter.After {
+			continue
+		}
+		users = append(users, user)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].UserID < users[j].UserID
+	})
+	if len(users) > filter.Limit {
+		users = users[:filter.Limit]
+	}
+
+	return users, nil
+}
+
+// usersOf returns the matching members of the team ordered by user_id.
+// Users without a team are members of none.
+func (db *DB) usersOf(teamName string, match func(models.User) bool) []models.User {
+	var users []models.User
+	for _, user := range db.users {
+		if user.TeamName != "" && user.TeamName == teamName && match(user) {
+			users = append(users, user)
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].UserID < users[j].UserID
+	})
+	return users
+}
+
+// teamExists reports whether a users.team_name value satisfies its foreign
+// key: an empty name stands for NULL.
+func (db *DB) teamExists(teamName string) bool {
+	if teamName == "" {
+		return true
+	}
+	_, ok := db.teams[teamName]
+	return ok
+}
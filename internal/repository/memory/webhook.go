@@ -0,0 +1,212 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+type WebhookRepository struct {
+	db *DB
+}
+
+func NewWebhookRepository(db *DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	if _, ok := r.db.teams[webhook.TeamName]; !ok {
+		return ErrForeignKey
+	}
+
+	now := time.Now()
+	r.db.lastWebhookID++
+	webhook.ID = r.db.lastWebhookID
+	webhook.CreatedAt = &now
+
+	r.db.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (r *WebhookRepository) GetByTeam(ctx context.Context, teamName string) ([]models.Webhook, error) {
+	if err := r.db.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.runlock(ctx)
+
+	var webhooks []models.Webhook
+	for _, webhook := range r.db.webhooks {
+		if webhook.TeamName == teamName {
+			webhook.Secret = ""
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].ID < webhooks[j].ID
+	})
+	return webhooks, nil
+}
+
+// Delete removes the webhook together with its deliveries.
+func (r *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	if _, ok := r.db.webhooks[id]; !ok {
+		return sql.ErrNoRows
+	}
+	r.db.deleteWebhook(id)
+
+	return nil
+}
+
+func (db *DB) deleteWebhook(id int64) {
+	delete(db.webhooks, id)
+
+	deliveries := db.deliveries[:0]
+	for _, delivery := range db.deliveries {
+		if delivery.webhookID != id {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	db.deliveries = deliveries
+}
+
+// Enqueue stores a pending delivery of the event for every webhook of its team.
+// Enqueueing the same event again is a no-op.
+func (r *WebhookRepository) Enqueue(ctx context.Context, event *models.Event, payload []byte) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	enqueued := make(map[int64]bool)
+	for _, delivery := range r.db.deliveries {
+		if delivery.eventID == event.ID {
+			enqueued[delivery.webhookID] = true
+		}
+	}
+
+	var webhookIDs []int64
+	for id, webhook := range r.db.webhooks {
+		if webhook.TeamName == event.TeamName && !enqueued[id] {
+			webhookIDs = append(webhookIDs, id)
+		}
+	}
+	sort.Slice(webhookIDs, func(i, j int) bool {
+		return webhookIDs[i] < webhookIDs[j]
+	})
+
+	now := time.Now()
+	for _, webhookID := range webhookIDs {
+		r.db.lastDeliveryID++
+		r.db.deliveries = append(r.db.deliveries, &deliveryRow{
+			id:            r.db.lastDeliveryID,
+			webhookID:     webhookID,
+			eventID:       event.ID,
+			eventType:     event.Type,
+			payload:       append([]byte(nil), payload...),
+			status:        models.DeliveryPending,
+			nextAttemptAt: now,
+		})
+	}
+
+	return nil
+}
+
+// ClaimDue returns up to limit pending deliveries that are due and pushes their
+// next attempt forward by lease, so that other dispatchers skip them meanwhile.
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	if err := r.db.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.db.unlock(ctx)
+
+	var due []*deliveryRow
+	for _, delivery := range r.db.deliveries {
+		if delivery.status == models.DeliveryPending && !delivery.nextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].nextAttemptAt.Equal(due[j].nextAttemptAt) {
+			return due[i].nextAttemptAt.Before(due[j].nextAttemptAt)
+		}
+		return due[i].id < due[j].id
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range due {
+		delivery.nextAttemptAt = now.Add(lease)
+
+		webhook := r.db.webhooks[delivery.webhookID]
+		deliveries = append(deliveries, models.WebhookDelivery{
+			ID:        delivery.id,
+			EventType: delivery.eventType,
+			Payload:   append([]byte(nil), delivery.payload...),
+			Attempts:  delivery.attempts,
+			URL:       webhook.URL,
+			Secret:    webhook.Secret,
+		})
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id int64, attempts int) error {
+	return r.update(ctx, id, func(delivery *deliveryRow) {
+		now := time.Now()
+		delivery.status = models.DeliveryDelivered
+		delivery.attempts = attempts
+		delivery.deliveredAt = &now
+		delivery.lastError = ""
+	})
+}
+
+// MarkRetry records a failed attempt and schedules the next one.
+func (r *WebhookRepository) MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return r.update(ctx, id, func(delivery *deliveryRow) {
+		delivery.attempts = attempts
+		delivery.nextAttemptAt = nextAttemptAt
+		delivery.lastError = lastError
+	})
+}
+
+// MarkFailed gives up on a delivery after the last allowed attempt.
+func (r *WebhookRepository) MarkFailed(ctx context.Context, id int64, attempts int, lastError string) error {
+	return r.update(ctx, id, func(delivery *deliveryRow) {
+		delivery.status = models.DeliveryFailed
+		delivery.attempts = attempts
+		delivery.lastError = lastError
+	})
+}
+
+func (r *WebhookRepository) update(ctx context.Context, id int64, apply func(delivery *deliveryRow)) error {
+	if err := r.db.lock(ctx); err != nil {
+		return err
+	}
+	defer r.db.unlock(ctx)
+
+	for _, delivery := range r.db.deliveries {
+		if delivery.id == id {
+			apply(delivery)
+			break
+		}
+	}
+	return nil
+}
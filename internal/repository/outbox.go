@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+
+	"pr-reviewer-service/internal/models"
+)
+
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// insertEvent writes the event to the outbox as part of tx. A nil event is a no-op.
+func insertEvent(ctx context.Context, tx executor, event *models.Event) error {
+	if event == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO outbox_events (event_id, event_type, team_name, pull_request_id, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err = tx.ExecContext(ctx, query, event.ID, event.Type, event.TeamName, event.PullRequestID, string(payload), event.OccurredAt)
+	return err
+}
+
+// insertEvents writes the events to the outbox as part of tx with a single
+// statement, in the given order.
+func insertEvents(ctx context.Context, tx executor, events []*models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(events))
+	types := make([]string, len(events))
+	teams := make([]string, len(events))
+	prIDs := make([]string, len(events))
+	payloads := make([]string, len(events))
+	createdAt := make([]string, len(events))
+	for i, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		ids[i] = event.ID
+		types[i] = event.Type
+		teams[i] = event.TeamName
+		prIDs[i] = event.PullRequestID
+		payloads[i] = string(payload)
+		createdAt[i] = event.OccurredAt.Format(time.RFC3339Nano)
+	}
+
+	query := `INSERT INTO outbox_events (event_id, event_type, team_name, pull_request_id, payload, created_at)
+		SELECT event_id, event_type, team_name, pull_request_id, payload, created_at
+		FROM unnest($1::varchar[], $2::varchar[], $3::varchar[], $4::varchar[], $5::text[], $6::timestamp[])
+		WITH ORDINALITY AS e(event_id, event_type, team_name, pull_request_id, payload, created_at, n)
+		ORDER BY n`
+
+	_, err := tx.ExecContext(ctx, query,
+		pq.Array(ids), pq.Array(types), pq.Array(teams), pq.Array(prIDs), pq.Array(payloads), pq.Array(createdAt),
+	)
+	return err
+}
+
+// ClaimBatch returns up to limit unprocessed events in the order they were
+// written and locks them for lease, so that other dispatchers skip them meanwhile.
+func (r *OutboxRepository) ClaimBatch(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEvent, error) {
+	query := `UPDATE outbox_events SET locked_until = $1
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE processed_at IS NULL AND (locked_until IS NULL OR locked_until <= $2)
+			ORDER BY id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, payload`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, now.Add(lease), now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var outboxEvent models.OutboxEvent
+		var payload string
+		if err := rows.Scan(&outboxEvent.ID, &payload); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(payload), &outboxEvent.Event); err != nil {
+			return nil, err
+		}
+		events = append(events, outboxEvent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the subquery order
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+func (r *OutboxRepository) MarkProcessed(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `UPDATE outbox_events SET processed_at = $1, locked_until = NULL WHERE id = ANY($2)`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now(), pq.Array(ids))
+	return err
+}
+
+// Release makes claimed events available again after retryAt.
+func (r *OutboxRepository) Release(ctx context.Context, ids []int64, retryAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `UPDATE outbox_events SET locked_until = $1 WHERE id = ANY($2)`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, retryAt, pq.Array(ids))
+	return err
+}
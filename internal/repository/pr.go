@@ -1,9 +1,15 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/lib/pq"
+
 	"pr-reviewer-service/internal/models"
 )
 
@@ -15,8 +21,11 @@ func NewPRRepository(db *sql.DB) *PRRepository {
 	return &PRRepository{db: db}
 }
 
-func (r *PRRepository) Create(pr *models.PullRequest) error {
-	tx, err := r.db.Begin()
+// Create inserts the pull request with its reviewers, flagging the ones listed
+// in FallbackReviewers, and, when event is not nil, records the event in the
+// outbox within the same transaction.
+func (r *PRRepository) Create(ctx context.Context, pr *models.PullRequest, event *models.Event) error {
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return err
 	}
@@ -24,37 +33,45 @@ func (r *PRRepository) Create(pr *models.PullRequest) error {
 		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
 	}()
 
-	query := `INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at) 
-		VALUES ($1, $2, $3, $4, $5)`
+	query := `INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, required_reviewers, created_at) 
+		VALUES ($1, $2, $3, $4, $5, $6)`
 
 	now := time.Now()
-	_, err = tx.Exec(query, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, now)
+	if pr.CreatedAt != nil {
+		now = *pr.CreatedAt
+	}
+	_, err = tx.ExecContext(ctx, query, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, pr.RequiredReviewers, now)
 	if err != nil {
-		return err
+		return mapError(err)
 	}
 
 	if len(pr.AssignedReviewers) > 0 {
 		for _, reviewerID := range pr.AssignedReviewers {
-			reviewerQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id) VALUES ($1, $2)`
-			_, err = tx.Exec(reviewerQuery, pr.PullRequestID, reviewerID)
+			reviewerQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id, from_fallback) VALUES ($1, $2, $3)`
+			fromFallback := slices.Contains(pr.FallbackReviewers, reviewerID)
+			_, err = tx.ExecContext(ctx, reviewerQuery, pr.PullRequestID, reviewerID, fromFallback)
 			if err != nil {
 				return err
 			}
 		}
 	}
 
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
 	pr.CreatedAt = &now
 	return tx.Commit()
 }
 
-func (r *PRRepository) GetByID(prID string) (*models.PullRequest, error) {
-	query := `SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at 
+func (r *PRRepository) GetByID(ctx context.Context, prID string) (*models.PullRequest, error) {
+	query := `SELECT pull_request_id, pull_request_name, author_id, status, required_reviewers, created_at, merged_at 
 		FROM pull_requests WHERE pull_request_id = $1`
 
 	pr := &models.PullRequest{}
-	err := r.db.QueryRow(query, prID).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, prID).Scan(
 		&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID,
-		&pr.Status, &pr.CreatedAt, &pr.MergedAt,
+		&pr.Status, &pr.RequiredReviewers, &pr.CreatedAt, &pr.MergedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -63,49 +80,141 @@ func (r *PRRepository) GetByID(prID string) (*models.PullRequest, error) {
 		return nil, err
 	}
 
-	reviewersQuery := `SELECT user_id FROM pr_reviewers WHERE pull_request_id = $1`
-	rows, err := r.db.Query(reviewersQuery, prID)
+	reviewersQuery := `SELECT rev.user_id, u.username, rev.assigned_at, rev.from_fallback, rev.review_state, rev.reviewed_at
+		FROM pr_reviewers rev
+		INNER JOIN users u ON u.user_id = rev.user_id
+		WHERE rev.pull_request_id = $1
+		ORDER BY rev.assigned_at, rev.user_id`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, reviewersQuery, prID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var reviewers []string
+	reviewers := []string{}
 	for rows.Next() {
-		var reviewerID string
-		if err := rows.Scan(&reviewerID); err != nil {
+		var reviewer models.Reviewer
+		if err := rows.Scan(
+			&reviewer.UserID, &reviewer.Username, &reviewer.AssignedAt,
+			&reviewer.FromFallback, &reviewer.ReviewState, &reviewer.ReviewedAt,
+		); err != nil {
 			return nil, err
 		}
-		reviewers = append(reviewers, reviewerID)
+		reviewers = append(reviewers, reviewer.UserID)
+		if reviewer.FromFallback {
+			pr.FallbackReviewers = append(pr.FallbackReviewers, reviewer.UserID)
+		}
+		pr.Reviewers = append(pr.Reviewers, reviewer)
 	}
 
 	pr.AssignedReviewers = reviewers
 	return pr, rows.Err()
 }
 
-func (r *PRRepository) Exists(prID string) (bool, error) {
+// GetByIDs returns the pull requests that exist among prIDs, ordered by
+// pull_request_id.
+func (r *PRRepository) GetByIDs(ctx context.Context, prIDs []string) ([]models.PullRequest, error) {
+	query := `SELECT pull_request_id, pull_request_name, author_id, status, required_reviewers, created_at, merged_at
+		FROM pull_requests WHERE pull_request_id = ANY($1)
+		ORDER BY pull_request_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, pq.Array(prIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []models.PullRequest
+	for rows.Next() {
+		var pr models.PullRequest
+		if err := rows.Scan(
+			&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID,
+			&pr.Status, &pr.RequiredReviewers, &pr.CreatedAt, &pr.MergedAt,
+		); err != nil {
+			return nil, err
+		}
+		pr.AssignedReviewers = []string{}
+		prs = append(prs, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadReviewers(ctx, prs); err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+func (r *PRRepository) Exists(ctx context.Context, prID string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)`
 
 	var exists bool
-	err := r.db.QueryRow(query, prID).Scan(&exists)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, prID).Scan(&exists)
 	return exists, err
 }
 
-func (r *PRRepository) UpdateStatus(prID, status string) error {
-	now := time.Now()
+// Lock takes a row lock on the pull request, held until the transaction
+// carried by ctx ends.
+func (r *PRRepository) Lock(ctx context.Context, prID string) (bool, error) {
+	query := `SELECT pull_request_id FROM pull_requests WHERE pull_request_id = $1 FOR UPDATE`
+
+	var id string
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, prID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LockAll takes row locks on the pull requests in pull_request_id order, held
+// until the transaction carried by ctx ends.
+func (r *PRRepository) LockAll(ctx context.Context, prIDs []string) error {
+	query := `SELECT pull_request_id FROM pull_requests WHERE pull_request_id = ANY($1)
+		ORDER BY pull_request_id FOR UPDATE`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, pq.Array(prIDs))
+	return err
+}
+
+// UpdateStatus sets the status, stamping merged_at with changedAt for MERGED,
+// and records the event in the outbox within the same transaction.
+func (r *PRRepository) UpdateStatus(ctx context.Context, prID, status string, changedAt time.Time, event *models.Event) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
 	query := `UPDATE pull_requests SET status = $1, merged_at = $2 WHERE pull_request_id = $3`
 
 	var mergedAt *time.Time
 	if status == models.StatusMerged {
-		mergedAt = &now
+		mergedAt = &changedAt
 	}
 
-	_, err := r.db.Exec(query, status, mergedAt, prID)
-	return err
+	if _, err = tx.ExecContext(ctx, query, status, mergedAt, prID); err != nil {
+		return err
+	}
+
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *PRRepository) ReplaceReviewer(prID, oldReviewerID, newReviewerID string) error {
-	tx, err := r.db.Begin()
+// ReplaceReviewer swaps a reviewer, logs the reassignment and records the event
+// in the outbox within the same transaction. fromFallback flags the new reviewer
+// as borrowed from a fallback team.
+func (r *PRRepository) ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string, fromFallback bool, event *models.Event) error {
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return err
 	}
@@ -114,36 +223,217 @@ func (r *PRRepository) ReplaceReviewer(prID, oldReviewerID, newReviewerID string
 	}()
 
 	deleteQuery := `DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND user_id = $2`
-	_, err = tx.Exec(deleteQuery, prID, oldReviewerID)
+	_, err = tx.ExecContext(ctx, deleteQuery, prID, oldReviewerID)
+	if err != nil {
+		return err
+	}
+
+	insertQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id, from_fallback) VALUES ($1, $2, $3)`
+	_, err = tx.ExecContext(ctx, insertQuery, prID, newReviewerID, fromFallback)
 	if err != nil {
 		return err
 	}
 
-	insertQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id) VALUES ($1, $2)`
-	_, err = tx.Exec(insertQuery, prID, newReviewerID)
+	logQuery := `INSERT INTO pr_reviewer_reassignments (pull_request_id, old_user_id, new_user_id) VALUES ($1, $2, $3)`
+	_, err = tx.ExecContext(ctx, logQuery, prID, oldReviewerID, newReviewerID)
 	if err != nil {
 		return err
 	}
 
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
-func (r *PRRepository) IsReviewerAssigned(prID, userID string) (bool, error) {
+// ReplaceReviewers applies a batch of reviewer swaps, logs the reassignments
+// and records the events in the outbox within the same transaction. It returns
+// sql.ErrNoRows when an old reviewer is no longer assigned.
+func (r *PRRepository) ReplaceReviewers(ctx context.Context, replacements []models.ReviewerReplacement, events []*models.Event) error {
+	if len(replacements) == 0 {
+		return nil
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	prIDs := make([]string, len(replacements))
+	oldIDs := make([]string, len(replacements))
+	newIDs := make([]string, len(replacements))
+	fromFallback := make([]bool, len(replacements))
+	for i, replacement := range replacements {
+		prIDs[i] = replacement.PullRequestID
+		oldIDs[i] = replacement.OldUserID
+		newIDs[i] = replacement.NewUserID
+		fromFallback[i] = replacement.FromFallback
+	}
+
+	deleteQuery := `DELETE FROM pr_reviewers rev
+		USING unnest($1::varchar[], $2::varchar[]) AS old(pull_request_id, user_id)
+		WHERE rev.pull_request_id = old.pull_request_id AND rev.user_id = old.user_id`
+	result, err := tx.ExecContext(ctx, deleteQuery, pq.Array(prIDs), pq.Array(oldIDs))
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected != int64(len(replacements)) {
+		return sql.ErrNoRows
+	}
+
+	insertQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id, from_fallback)
+		SELECT * FROM unnest($1::varchar[], $2::varchar[], $3::boolean[])`
+	if _, err = tx.ExecContext(ctx, insertQuery, pq.Array(prIDs), pq.Array(newIDs), pq.Array(fromFallback)); err != nil {
+		return mapError(err)
+	}
+
+	logQuery := `INSERT INTO pr_reviewer_reassignments (pull_request_id, old_user_id, new_user_id)
+		SELECT * FROM unnest($1::varchar[], $2::varchar[], $3::varchar[])`
+	if _, err = tx.ExecContext(ctx, logQuery, pq.Array(prIDs), pq.Array(oldIDs), pq.Array(newIDs)); err != nil {
+		return err
+	}
+
+	if err := insertEvents(ctx, tx, events); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddReviewers assigns more reviewers to the pull request, flagging the ones
+// listed in fallbackIDs, and records the event in the outbox within the same
+// transaction.
+func (r *PRRepository) AddReviewers(ctx context.Context, prID string, reviewerIDs, fallbackIDs []string, event *models.Event) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	query := `INSERT INTO pr_reviewers (pull_request_id, user_id, from_fallback) VALUES ($1, $2, $3)`
+	for _, reviewerID := range reviewerIDs {
+		fromFallback := slices.Contains(fallbackIDs, reviewerID)
+		if _, err := tx.ExecContext(ctx, query, prID, reviewerID, fromFallback); err != nil {
+			return mapError(err)
+		}
+	}
+
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveReviewer unassigns the reviewer and records the event in the outbox
+// within the same transaction.
+func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, userID string, event *models.Event) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	query := `DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND user_id = $2`
+	result, err := tx.ExecContext(ctx, query, prID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SubmitReview records the reviewer's decision, stamped with reviewedAt, and
+// the event in the outbox within the same transaction.
+func (r *PRRepository) SubmitReview(ctx context.Context, prID, userID, state string, reviewedAt time.Time, event *models.Event) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	query := `UPDATE pr_reviewers SET review_state = $1, reviewed_at = $2 WHERE pull_request_id = $3 AND user_id = $4`
+	result, err := tx.ExecContext(ctx, query, state, reviewedAt, prID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PRRepository) UpdateRequiredReviewers(ctx context.Context, prID string, required int) error {
+	query := `UPDATE pull_requests SET required_reviewers = $1 WHERE pull_request_id = $2`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, required, prID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PRRepository) IsReviewerAssigned(ctx context.Context, prID, userID string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM pr_reviewers WHERE pull_request_id = $1 AND user_id = $2)`
 
 	var exists bool
-	err := r.db.QueryRow(query, prID, userID).Scan(&exists)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, prID, userID).Scan(&exists)
 	return exists, err
 }
 
-func (r *PRRepository) GetByReviewer(userID string) ([]models.PullRequestShort, error) {
-	query := `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status
+func (r *PRRepository) GetByReviewer(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+	query := `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, rev.review_state
 		FROM pull_requests pr
 		INNER JOIN pr_reviewers rev ON pr.pull_request_id = rev.pull_request_id
 		WHERE rev.user_id = $1
 		ORDER BY pr.created_at DESC`
 
-	rows, err := r.db.Query(query, userID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -152,7 +442,7 @@ func (r *PRRepository) GetByReviewer(userID string) ([]models.PullRequestShort,
 	var prs []models.PullRequestShort
 	for rows.Next() {
 		var pr models.PullRequestShort
-		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status); err != nil {
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.ReviewState); err != nil {
 			return nil, err
 		}
 		prs = append(prs, pr)
@@ -160,3 +450,359 @@ func (r *PRRepository) GetByReviewer(userID string) ([]models.PullRequestShort,
 
 	return prs, rows.Err()
 }
+
+func (r *PRRepository) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	query := `SELECT rev.user_id, COUNT(*)
+		FROM pr_reviewers rev
+		INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+		WHERE rev.user_id = ANY($1) AND pr.status = $2
+		GROUP BY rev.user_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, pq.Array(userIDs), models.StatusOpen)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int, len(userIDs))
+	for rows.Next() {
+		var userID string
+		var count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+		counts[userID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *PRRepository) List(ctx context.Context, filter *models.PullRequestFilter) ([]models.PullRequest, error) {
+	var conditions []string
+	var args []any
+
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	if filter.Status != "" {
+		addCondition(`pr.status = ?`, filter.Status)
+	}
+	if filter.AuthorID != "" {
+		addCondition(`pr.author_id = ?`, filter.AuthorID)
+	}
+	if filter.TeamName != "" {
+		addCondition(`EXISTS(SELECT 1 FROM users u WHERE u.user_id = pr.author_id AND u.team_name = ?)`, filter.TeamName)
+	}
+	if filter.ReviewerID != "" {
+		addCondition(`EXISTS(SELECT 1 FROM pr_reviewers rev WHERE rev.pull_request_id = pr.pull_request_id AND rev.user_id = ?)`, filter.ReviewerID)
+	}
+	if filter.CreatedFrom != nil {
+		addCondition(`pr.created_at >= ?`, *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		addCondition(`pr.created_at < ?`, *filter.CreatedTo)
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.Order == models.OrderDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	orderBy := fmt.Sprintf(`pr.created_at %s, pr.pull_request_id %s`, direction, direction)
+	if filter.SortBy == models.SortByPullRequestID {
+		orderBy = fmt.Sprintf(`pr.pull_request_id %s`, direction)
+	}
+
+	if filter.After != nil {
+		if filter.SortBy == models.SortByPullRequestID {
+			addCondition(`pr.pull_request_id `+comparison+` ?`, filter.After.PullRequestID)
+		} else {
+			args = append(args, filter.After.CreatedAt, filter.After.PullRequestID)
+			conditions = append(conditions, fmt.Sprintf(`(pr.created_at, pr.pull_request_id) %s ($%d, $%d)`,
+				comparison, len(args)-1, len(args)))
+		}
+	}
+
+	query := `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.required_reviewers, pr.created_at, pr.merged_at
+		FROM pull_requests pr`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(` ORDER BY %s LIMIT $%d`, orderBy, len(args))
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []models.PullRequest
+	for rows.Next() {
+		var pr models.PullRequest
+		if err := rows.Scan(
+			&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID,
+			&pr.Status, &pr.RequiredReviewers, &pr.CreatedAt, &pr.MergedAt,
+		); err != nil {
+			return nil, err
+		}
+		pr.AssignedReviewers = []string{}
+		prs = append(prs, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadReviewers(ctx, prs); err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+// loadReviewers fills AssignedReviewers and Reviewers for a batch of pull requests with a single query.
+func (r *PRRepository) loadReviewers(ctx context.Context, prs []models.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(prs))
+	prIDs := make([]string, len(prs))
+	for i := range prs {
+		index[prs[i].PullRequestID] = i
+		prIDs[i] = prs[i].PullRequestID
+	}
+
+	query := `SELECT rev.pull_request_id, rev.user_id, u.username, rev.assigned_at, rev.from_fallback, rev.review_state, rev.reviewed_at
+		FROM pr_reviewers rev
+		INNER JOIN users u ON u.user_id = rev.user_id
+		WHERE rev.pull_request_id = ANY($1)
+		ORDER BY rev.assigned_at, rev.user_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, pq.Array(prIDs))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prID string
+		var reviewer models.Reviewer
+		if err := rows.Scan(
+			&prID, &reviewer.UserID, &reviewer.Username, &reviewer.AssignedAt,
+			&reviewer.FromFallback, &reviewer.ReviewState, &reviewer.ReviewedAt,
+		); err != nil {
+			return err
+		}
+		pr := &prs[index[prID]]
+		pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer.UserID)
+		if reviewer.FromFallback {
+			pr.FallbackReviewers = append(pr.FallbackReviewers, reviewer.UserID)
+		}
+		pr.Reviewers = append(pr.Reviewers, reviewer)
+	}
+
+	return rows.Err()
+}
+
+// GetReviewerStats aggregates review load per user, optionally limited to one team.
+// Reassigned-away and removed reviews no longer have a pr_reviewers row, so they
+// are added to the total from the reassignments log and the history.
+func (r *PRRepository) GetReviewerStats(ctx context.Context, teamName string) ([]models.ReviewerStats, error) {
+	query := `SELECT u.user_id, u.username, COALESCE(u.team_name, ''),
+			COALESCE(a.total, 0) + COALESCE(ra.reassigned, 0) + COALESCE(rm.removed, 0),
+			COALESCE(a.open, 0),
+			COALESCE(ra.reassigned, 0),
+			a.avg_merge_seconds
+		FROM users u
+		LEFT JOIN (
+			SELECT rev.user_id,
+				COUNT(*) AS total,
+				COUNT(*) FILTER (WHERE pr.status = $1) AS open,
+				AVG(EXTRACT(EPOCH FROM (pr.merged_at - rev.assigned_at))) FILTER (WHERE pr.status = $2) AS avg_merge_seconds
+			FROM pr_reviewers rev
+			INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+			GROUP BY rev.user_id
+		) a ON a.user_id = u.user_id
+		LEFT JOIN (
+			SELECT old_user_id, COUNT(*) AS reassigned
+			FROM pr_reviewer_reassignments
+			GROUP BY old_user_id
+		) ra ON ra.old_user_id = u.user_id
+		LEFT JOIN (
+			SELECT user_id, COUNT(*) AS removed
+			FROM pr_events
+			WHERE action = $3
+			GROUP BY user_id
+		) rm ON rm.user_id = u.user_id`
+	args := []any{models.StatusOpen, models.StatusMerged, models.HistoryReviewerRemoved}
+
+	if teamName != "" {
+		query += ` WHERE u.team_name = $4`
+		args = append(args, teamName)
+	}
+	query += ` ORDER BY COALESCE(u.team_name, ''), u.user_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []models.ReviewerStats
+	for rows.Next() {
+		var st models.ReviewerStats
+		if err := rows.Scan(
+			&st.UserID, &st.Username, &st.TeamName,
+			&st.TotalAssignments, &st.OpenAssignments, &st.ReassignedAway, &st.AvgTimeToMergeSeconds,
+		); err != nil {
+			return nil, err
+		}
+		stats = append(stats, st)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetOpenReviewsOf returns every reviewer assignment on the OPEN pull requests
+// reviewed by any of the given users, including assignments of other reviewers.
+func (r *PRRepository) GetOpenReviewsOf(ctx context.Context, userIDs []string) ([]models.OpenReview, error) {
+	query := `SELECT rev.pull_request_id, pr.author_id, rev.user_id
+		FROM pr_reviewers rev
+		INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+		WHERE pr.status = $1 AND rev.pull_request_id IN (
+			SELECT pull_request_id FROM pr_reviewers WHERE user_id = ANY($2)
+		)
+		ORDER BY rev.pull_request_id, rev.user_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, models.StatusOpen, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []models.OpenReview
+	for rows.Next() {
+		var review models.OpenReview
+		if err := rows.Scan(&review.PullRequestID, &review.AuthorID, &review.ReviewerID); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
+
+// HasOpenPullRequests reports whether any of the users authors or reviews an
+// OPEN pull request.
+func (r *PRRepository) HasOpenPullRequests(ctx context.Context, userIDs []string) (bool, error) {
+	query := `SELECT EXISTS(
+		SELECT 1 FROM pull_requests pr
+		WHERE pr.status = $1 AND (
+			pr.author_id = ANY($2)
+			OR EXISTS(SELECT 1 FROM pr_reviewers rev WHERE rev.pull_request_id = pr.pull_request_id AND rev.user_id = ANY($2))
+		)
+	)`
+
+	var exists bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, models.StatusOpen, pq.Array(userIDs)).Scan(&exists)
+	return exists, err
+}
+
+// GetUnderstaffed returns the OPEN pull requests with fewer reviewers than
+// required, oldest first; a non-empty teamName keeps the ones of its authors.
+func (r *PRRepository) GetUnderstaffed(ctx context.Context, teamName string) ([]string, error) {
+	query := `SELECT pr.pull_request_id FROM pull_requests pr
+		WHERE pr.status = $1
+			AND (SELECT COUNT(*) FROM pr_reviewers rev WHERE rev.pull_request_id = pr.pull_request_id) < pr.required_reviewers
+			AND ($2 = '' OR EXISTS(SELECT 1 FROM users u WHERE u.user_id = pr.author_id AND u.team_name = $2))
+		ORDER BY pr.created_at, pr.pull_request_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, models.StatusOpen, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prIDs []string
+	for rows.Next() {
+		var prID string
+		if err := rows.Scan(&prID); err != nil {
+			return nil, err
+		}
+		prIDs = append(prIDs, prID)
+	}
+
+	return prIDs, rows.Err()
+}
+
+// AddHistory appends entries to the history of their pull requests in the
+// given order with a single statement.
+func (r *PRRepository) AddHistory(ctx context.Context, entries []models.HistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	prIDs := make([]string, len(entries))
+	actions := make([]string, len(entries))
+	userIDs := make([]string, len(entries))
+	oldUserIDs := make([]string, len(entries))
+	statuses := make([]string, len(entries))
+	reviewStates := make([]string, len(entries))
+	reasons := make([]string, len(entries))
+	occurredAt := make([]string, len(entries))
+	for i, entry := range entries {
+		prIDs[i] = entry.PullRequestID
+		actions[i] = entry.Action
+		userIDs[i] = entry.UserID
+		oldUserIDs[i] = entry.OldUserID
+		statuses[i] = entry.Status
+		reviewStates[i] = entry.ReviewState
+		reasons[i] = entry.Reason
+		occurredAt[i] = entry.OccurredAt.Format(time.RFC3339Nano)
+	}
+
+	query := `INSERT INTO pr_events (pull_request_id, action, user_id, old_user_id, status, review_state, reason, occurred_at)
+		SELECT pull_request_id, action, NULLIF(user_id, ''), NULLIF(old_user_id, ''), NULLIF(status, ''),
+			NULLIF(review_state, ''), NULLIF(reason, ''), occurred_at
+		FROM unnest($1::varchar[], $2::varchar[], $3::varchar[], $4::varchar[], $5::varchar[], $6::varchar[], $7::varchar[], $8::timestamp[])
+		WITH ORDINALITY AS e(pull_request_id, action, user_id, old_user_id, status, review_state, reason, occurred_at, n)
+		ORDER BY n`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		pq.Array(prIDs), pq.Array(actions), pq.Array(userIDs), pq.Array(oldUserIDs),
+		pq.Array(statuses), pq.Array(reviewStates), pq.Array(reasons), pq.Array(occurredAt),
+	)
+	return err
+}
+
+// GetHistory returns the history of the pull request, oldest first.
+func (r *PRRepository) GetHistory(ctx context.Context, prID string) ([]models.HistoryEntry, error) {
+	query := `SELECT pull_request_id, action, COALESCE(user_id, ''), COALESCE(old_user_id, ''),
+			COALESCE(status, ''), COALESCE(review_state, ''), COALESCE(reason, ''), occurred_at
+		FROM pr_events
+		WHERE pull_request_id = $1
+		ORDER BY id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.HistoryEntry
+	for rows.Next() {
+		var entry models.HistoryEntry
+		if err := rows.Scan(
+			&entry.PullRequestID, &entry.Action, &entry.UserID, &entry.OldUserID,
+			&entry.Status, &entry.ReviewState, &entry.Reason, &entry.OccurredAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
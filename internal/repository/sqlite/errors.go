@@ -0,0 +1,23 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"pr-reviewer-service/internal/service"
+)
+
+// mapError translates driver errors the service layer relies on.
+func mapError(err error) error {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY, sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+			return fmt.Errorf("%w: %s", service.ErrDuplicateKey, sqliteErr.Error())
+		}
+	}
+	return err
+}
@@ -0,0 +1,182 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// insertEvent writes the event to the outbox as part of tx. A nil event is a no-op.
+func insertEvent(ctx context.Context, tx executor, event *models.Event) error {
+	if event == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO outbox_events (event_id, event_type, team_name, pull_request_id, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err = tx.ExecContext(ctx, query, event.ID, event.Type, event.TeamName, event.PullRequestID, string(payload), event.OccurredAt.UTC())
+	return err
+}
+
+// insertEvents writes the events to the outbox as part of tx with multi-row
+// statements, in the given order.
+func insertEvents(ctx context.Context, tx executor, events []*models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([][]any, len(events))
+	for i, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		rows[i] = []any{event.ID, event.Type, event.TeamName, event.PullRequestID, string(payload), event.OccurredAt.UTC()}
+	}
+
+	query := `INSERT INTO outbox_events (event_id, event_type, team_name, pull_request_id, payload, created_at)
+		VALUES %s`
+
+	_, err := execValues(ctx, tx, query, rows)
+	return err
+}
+
+// ClaimBatch returns up to limit unprocessed events in the order they were
+// written and locks them for lease, so that other dispatchers skip them meanwhile.
+func (r *OutboxRepository) ClaimBatch(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEvent, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	query := `SELECT id, payload FROM outbox_events
+		WHERE processed_at IS NULL AND (locked_until IS NULL OR locked_until <= $1)
+		ORDER BY id
+		LIMIT $2`
+
+	rows, err := tx.QueryContext(ctx, query, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var outboxEvent models.OutboxEvent
+		var payload string
+		if err := rows.Scan(&outboxEvent.ID, &payload); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(payload), &outboxEvent.Event); err != nil {
+			return nil, err
+		}
+		events = append(events, outboxEvent)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(events))
+	for i, outboxEvent := range events {
+		ids[i] = outboxEvent.ID
+	}
+
+	if len(ids) > 0 {
+		list, args := in([]any{now.Add(lease).UTC()}, ids)
+		if _, err := tx.ExecContext(ctx, `UPDATE outbox_events SET locked_until = $1 WHERE id IN `+list, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	return events, tx.Commit()
+}
+
+func (r *OutboxRepository) MarkProcessed(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	list, args := in([]any{time.Now().UTC()}, ids)
+	_, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE outbox_events SET processed_at = $1, locked_until = NULL WHERE id IN `+list, args...)
+	return err
+}
+
+// Release makes claimed events available again after retryAt.
+func (r *OutboxRepository) Release(ctx context.Context, ids []int64, retryAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	list, args := in([]any{retryAt.UTC()}, ids)
+	_, err := conn(ctx, r.db).ExecContext(ctx, `UPDATE outbox_events SET locked_until = $1 WHERE id IN `+list, args...)
+	return err
+}
+
+// in appends values to args and returns the matching "($i, $j, ...)" list,
+// which stands in for Postgres' = ANY($i) with an array.
+func in[T any](args []any, values []T) (string, []any) {
+	if len(values) == 0 {
+		return `(NULL)`, args
+	}
+
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		args = append(args, value)
+		placeholders[i] = fmt.Sprintf("$%d", len(args))
+	}
+	return `(` + strings.Join(placeholders, `, `) + `)`, args
+}
+
+// valuesChunk caps the rows of one multi-row statement, keeping it well below
+// SQLite's limit on bound parameters.
+const valuesChunk = 100
+
+// execValues runs query once per chunk of rows, with %s in query replaced by
+// the chunk's "(?, ?, ...), ..." list, and returns the total of affected rows.
+// The placeholders are positional because the driver matches every $n against
+// every argument, which gets slow with the parameter count of a whole chunk.
+func execValues(ctx context.Context, tx executor, query string, rows [][]any) (int64, error) {
+	var total int64
+	for chunk := range slices.Chunk(rows, valuesChunk) {
+		tuples := make([]string, len(chunk))
+		var args []any
+		for i, row := range chunk {
+			tuples[i] = `(` + strings.TrimSuffix(strings.Repeat(`?, `, len(row)), `, `) + `)`
+			args = append(args, row...)
+		}
+
+		result, err := tx.ExecContext(ctx, fmt.Sprintf(query, strings.Join(tuples, `, `)), args...)
+		if err != nil {
+			return 0, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		total += affected
+	}
+	return total, nil
+}
@@ -0,0 +1,781 @@
+// Package sqlite implements the repositories on SQLite for local development
+// and edge deployments. Queries follow the Postgres repositories, with
+// IN lists in place of arrays and plain statements in place of unnest.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+type PRRepository struct {
+	db *sql.DB
+}
+
+func NewPRRepository(db *sql.DB) *PRRepository {
+	return &PRRepository{db: db}
+}
+
+// Create inserts the pull request with its reviewers, flagging the ones listed
+// in FallbackReviewers, and, when event is not nil, records the event in the
+// outbox within the same transaction.
+func (r *PRRepository) Create(ctx context.Context, pr *models.PullRequest, event *models.Event) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	query := `INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, required_reviewers, created_at) 
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	assignedAt := time.Now().UTC()
+	now := assignedAt
+	if pr.CreatedAt != nil {
+		now = *pr.CreatedAt
+	}
+	_, err = tx.ExecContext(ctx, query, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, pr.RequiredReviewers, now.UTC())
+	if err != nil {
+		return mapError(err)
+	}
+
+	if len(pr.AssignedReviewers) > 0 {
+		for _, reviewerID := range pr.AssignedReviewers {
+			reviewerQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at, from_fallback) VALUES ($1, $2, $3, $4)`
+			fromFallback := slices.Contains(pr.FallbackReviewers, reviewerID)
+			_, err = tx.ExecContext(ctx, reviewerQuery, pr.PullRequestID, reviewerID, assignedAt, fromFallback)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	pr.CreatedAt = &now
+	return tx.Commit()
+}
+
+func (r *PRRepository) GetByID(ctx context.Context, prID string) (*models.PullRequest, error) {
+	query := `SELECT pull_request_id, pull_request_name, author_id, status, required_reviewers, created_at, merged_at 
+		FROM pull_requests WHERE pull_request_id = $1`
+
+	pr := &models.PullRequest{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, prID).Scan(
+		&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID,
+		&pr.Status, &pr.RequiredReviewers, &pr.CreatedAt, &pr.MergedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reviewersQuery := `SELECT rev.user_id, u.username, rev.assigned_at, rev.from_fallback, rev.review_state, rev.reviewed_at
+		FROM pr_reviewers rev
+		INNER JOIN users u ON u.user_id = rev.user_id
+		WHERE rev.pull_request_id = $1
+		ORDER BY rev.assigned_at, rev.user_id`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, reviewersQuery, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviewers := []string{}
+	for rows.Next() {
+		var reviewer models.Reviewer
+		if err := rows.Scan(
+			&reviewer.UserID, &reviewer.Username, &reviewer.AssignedAt,
+			&reviewer.FromFallback, &reviewer.ReviewState, &reviewer.ReviewedAt,
+		); err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, reviewer.UserID)
+		if reviewer.FromFallback {
+			pr.FallbackReviewers = append(pr.FallbackReviewers, reviewer.UserID)
+		}
+		pr.Reviewers = append(pr.Reviewers, reviewer)
+	}
+
+	pr.AssignedReviewers = reviewers
+	return pr, rows.Err()
+}
+
+// GetByIDs returns the pull requests that exist among prIDs, ordered by
+// pull_request_id.
+func (r *PRRepository) GetByIDs(ctx context.Context, prIDs []string) ([]models.PullRequest, error) {
+	list, args := in(nil, prIDs)
+	query := `SELECT pull_request_id, pull_request_name, author_id, status, required_reviewers, created_at, merged_at
+		FROM pull_requests WHERE pull_request_id IN ` + list + `
+		ORDER BY pull_request_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []models.PullRequest
+	for rows.Next() {
+		var pr models.PullRequest
+		if err := rows.Scan(
+			&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID,
+			&pr.Status, &pr.RequiredReviewers, &pr.CreatedAt, &pr.MergedAt,
+		); err != nil {
+			return nil, err
+		}
+		pr.AssignedReviewers = []string{}
+		prs = append(prs, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadReviewers(ctx, prs); err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+func (r *PRRepository) Exists(ctx context.Context, prID string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)`
+
+	var exists bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, prID).Scan(&exists)
+	return exists, err
+}
+
+// Lock only checks that the pull request exists: SQLite has no row locks, and
+// units of work already run one at a time on the single connection.
+func (r *PRRepository) Lock(ctx context.Context, prID string) (bool, error) {
+	return r.Exists(ctx, prID)
+}
+
+// LockAll is a no-op for the same reason as Lock.
+func (r *PRRepository) LockAll(context.Context, []string) error {
+	return nil
+}
+
+// UpdateStatus sets the status, stamping merged_at with changedAt for MERGED,
+// and records the event in the outbox within the same transaction.
+func (r *PRRepository) UpdateStatus(ctx context.Context, prID, status string, changedAt time.Time, event *models.Event) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	query := `UPDATE pull_requests SET status = $1, merged_at = $2 WHERE pull_request_id = $3`
+
+	var mergedAt *time.Time
+	if status == models.StatusMerged {
+		changedAt = changedAt.UTC()
+		mergedAt = &changedAt
+	}
+
+	if _, err = tx.ExecContext(ctx, query, status, mergedAt, prID); err != nil {
+		return err
+	}
+
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceReviewer swaps a reviewer, logs the reassignment and records the event
+// in the outbox within the same transaction. fromFallback flags the new reviewer
+// as borrowed from a fallback team.
+func (r *PRRepository) ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string, fromFallback bool, event *models.Event) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	deleteQuery := `DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND user_id = $2`
+	_, err = tx.ExecContext(ctx, deleteQuery, prID, oldReviewerID)
+	if err != nil {
+		return err
+	}
+
+	insertQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at, from_fallback) VALUES ($1, $2, $3, $4)`
+	_, err = tx.ExecContext(ctx, insertQuery, prID, newReviewerID, time.Now().UTC(), fromFallback)
+	if err != nil {
+		return err
+	}
+
+	logQuery := `INSERT INTO pr_reviewer_reassignments (pull_request_id, old_user_id, new_user_id) VALUES ($1, $2, $3)`
+	_, err = tx.ExecContext(ctx, logQuery, prID, oldReviewerID, newReviewerID)
+	if err != nil {
+		return err
+	}
+
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceReviewers applies a batch of reviewer swaps, logs the reassignments
+// and records the events in the outbox within the same transaction. It returns
+// sql.ErrNoRows when an old reviewer is no longer assigned.
+func (r *PRRepository) ReplaceReviewers(ctx context.Context, replacements []models.ReviewerReplacement, events []*models.Event) error {
+	if len(replacements) == 0 {
+		return nil
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	assignedAt := time.Now().UTC()
+	oldRows := make([][]any, len(replacements))
+	newRows := make([][]any, len(replacements))
+	logRows := make([][]any, len(replacements))
+	for i, replacement := range replacements {
+		oldRows[i] = []any{replacement.PullRequestID, replacement.OldUserID}
+		newRows[i] = []any{replacement.PullRequestID, replacement.NewUserID, assignedAt, replacement.FromFallback}
+		logRows[i] = []any{replacement.PullRequestID, replacement.OldUserID, replacement.NewUserID}
+	}
+
+	deleteQuery := `DELETE FROM pr_reviewers WHERE (pull_request_id, user_id) IN (VALUES %s)`
+	deleted, err := execValues(ctx, tx, deleteQuery, oldRows)
+	if err != nil {
+		return err
+	}
+	if deleted != int64(len(replacements)) {
+		return sql.ErrNoRows
+	}
+
+	insertQuery := `INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at, from_fallback) VALUES %s`
+	if _, err = execValues(ctx, tx, insertQuery, newRows); err != nil {
+		return mapError(err)
+	}
+
+	logQuery := `INSERT INTO pr_reviewer_reassignments (pull_request_id, old_user_id, new_user_id) VALUES %s`
+	if _, err = execValues(ctx, tx, logQuery, logRows); err != nil {
+		return err
+	}
+
+	if err := insertEvents(ctx, tx, events); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddReviewers assigns more reviewers to the pull request, flagging the ones
+// listed in fallbackIDs, and records the event in the outbox within the same
+// transaction.
+func (r *PRRepository) AddReviewers(ctx context.Context, prID string, reviewerIDs, fallbackIDs []string, event *models.Event) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	assignedAt := time.Now().UTC()
+	query := `INSERT INTO pr_reviewers (pull_request_id, user_id, assigned_at, from_fallback) VALUES ($1, $2, $3, $4)`
+	for _, reviewerID := range reviewerIDs {
+		fromFallback := slices.Contains(fallbackIDs, reviewerID)
+		if _, err := tx.ExecContext(ctx, query, prID, reviewerID, assignedAt, fromFallback); err != nil {
+			return mapError(err)
+		}
+	}
+
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveReviewer unassigns the reviewer and records the event in the outbox
+// within the same transaction.
+func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, userID string, event *models.Event) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	query := `DELETE FROM pr_reviewers WHERE pull_request_id = $1 AND user_id = $2`
+	result, err := tx.ExecContext(ctx, query, prID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SubmitReview records the reviewer's decision, stamped with reviewedAt, and
+// the event in the outbox within the same transaction.
+func (r *PRRepository) SubmitReview(ctx context.Context, prID, userID, state string, reviewedAt time.Time, event *models.Event) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	query := `UPDATE pr_reviewers SET review_state = $1, reviewed_at = $2 WHERE pull_request_id = $3 AND user_id = $4`
+	result, err := tx.ExecContext(ctx, query, state, reviewedAt.UTC(), prID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := insertEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PRRepository) UpdateRequiredReviewers(ctx context.Context, prID string, required int) error {
+	query := `UPDATE pull_requests SET required_reviewers = $1 WHERE pull_request_id = $2`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, required, prID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *PRRepository) IsReviewerAssigned(ctx context.Context, prID, userID string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM pr_reviewers WHERE pull_request_id = $1 AND user_id = $2)`
+
+	var exists bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, prID, userID).Scan(&exists)
+	return exists, err
+}
+
+func (r *PRRepository) GetByReviewer(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+	query := `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, rev.review_state
+		FROM pull_requests pr
+		INNER JOIN pr_reviewers rev ON pr.pull_request_id = rev.pull_request_id
+		WHERE rev.user_id = $1
+		ORDER BY pr.created_at DESC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []models.PullRequestShort
+	for rows.Next() {
+		var pr models.PullRequestShort
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.ReviewState); err != nil {
+			return nil, err
+		}
+		prs = append(prs, pr)
+	}
+
+	return prs, rows.Err()
+}
+
+func (r *PRRepository) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	list, args := in([]any{models.StatusOpen}, userIDs)
+	query := `SELECT rev.user_id, COUNT(*)
+		FROM pr_reviewers rev
+		INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+		WHERE pr.status = $1 AND rev.user_id IN ` + list + `
+		GROUP BY rev.user_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int, len(userIDs))
+	for rows.Next() {
+		var userID string
+		var count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+		counts[userID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *PRRepository) List(ctx context.Context, filter *models.PullRequestFilter) ([]models.PullRequest, error) {
+	var conditions []string
+	var args []any
+
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	if filter.Status != "" {
+		addCondition(`pr.status = ?`, filter.Status)
+	}
+	if filter.AuthorID != "" {
+		addCondition(`pr.author_id = ?`, filter.AuthorID)
+	}
+	if filter.TeamName != "" {
+		addCondition(`EXISTS(SELECT 1 FROM users u WHERE u.user_id = pr.author_id AND u.team_name = ?)`, filter.TeamName)
+	}
+	if filter.ReviewerID != "" {
+		addCondition(`EXISTS(SELECT 1 FROM pr_reviewers rev WHERE rev.pull_request_id = pr.pull_request_id AND rev.user_id = ?)`, filter.ReviewerID)
+	}
+	if filter.CreatedFrom != nil {
+		addCondition(`pr.created_at >= ?`, filter.CreatedFrom.UTC())
+	}
+	if filter.CreatedTo != nil {
+		addCondition(`pr.created_at < ?`, filter.CreatedTo.UTC())
+	}
+
+	direction, comparison := "ASC", ">"
+	if filter.Order == models.OrderDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	orderBy := fmt.Sprintf(`pr.created_at %s, pr.pull_request_id %s`, direction, direction)
+	if filter.SortBy == models.SortByPullRequestID {
+		orderBy = fmt.Sprintf(`pr.pull_request_id %s`, direction)
+	}
+
+	if filter.After != nil {
+		if filter.SortBy == models.SortByPullRequestID {
+			addCondition(`pr.pull_request_id `+comparison+` ?`, filter.After.PullRequestID)
+		} else {
+			args = append(args, filter.After.CreatedAt.UTC(), filter.After.PullRequestID)
+			conditions = append(conditions, fmt.Sprintf(`(pr.created_at, pr.pull_request_id) %s ($%d, $%d)`,
+				comparison, len(args)-1, len(args)))
+		}
+	}
+
+	query := `SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.required_reviewers, pr.created_at, pr.merged_at
+		FROM pull_requests pr`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(` ORDER BY %s LIMIT $%d`, orderBy, len(args))
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prs []models.PullRequest
+	for rows.Next() {
+		var pr models.PullRequest
+		if err := rows.Scan(
+			&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID,
+			&pr.Status, &pr.RequiredReviewers, &pr.CreatedAt, &pr.MergedAt,
+		); err != nil {
+			return nil, err
+		}
+		pr.AssignedReviewers = []string{}
+		prs = append(prs, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadReviewers(ctx, prs); err != nil {
+		return nil, err
+	}
+
+	return prs, nil
+}
+
+// loadReviewers fills AssignedReviewers and Reviewers for a batch of pull requests with a single query.
+func (r *PRRepository) loadReviewers(ctx context.Context, prs []models.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(prs))
+	prIDs := make([]string, len(prs))
+	for i := range prs {
+		index[prs[i].PullRequestID] = i
+		prIDs[i] = prs[i].PullRequestID
+	}
+
+	list, args := in(nil, prIDs)
+	query := `SELECT rev.pull_request_id, rev.user_id, u.username, rev.assigned_at, rev.from_fallback, rev.review_state, rev.reviewed_at
+		FROM pr_reviewers rev
+		INNER JOIN users u ON u.user_id = rev.user_id
+		WHERE rev.pull_request_id IN ` + list + `
+		ORDER BY rev.assigned_at, rev.user_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prID string
+		var reviewer models.Reviewer
+		if err := rows.Scan(
+			&prID, &reviewer.UserID, &reviewer.Username, &reviewer.AssignedAt,
+			&reviewer.FromFallback, &reviewer.ReviewState, &reviewer.ReviewedAt,
+		); err != nil {
+			return err
+		}
+		pr := &prs[index[prID]]
+		pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer.UserID)
+		if reviewer.FromFallback {
+			pr.FallbackReviewers = append(pr.FallbackReviewers, reviewer.UserID)
+		}
+		pr.Reviewers = append(pr.Reviewers, reviewer)
+	}
+
+	return rows.Err()
+}
+
+// GetReviewerStats aggregates review load per user, optionally limited to one team.
+// Reassigned-away and removed reviews no longer have a pr_reviewers row, so they
+// are added to the total from the reassignments log and the history.
+func (r *PRRepository) GetReviewerStats(ctx context.Context, teamName string) ([]models.ReviewerStats, error) {
+	query := `SELECT u.user_id, u.username, COALESCE(u.team_name, ''),
+			COALESCE(a.total, 0) + COALESCE(ra.reassigned, 0) + COALESCE(rm.removed, 0),
+			COALESCE(a.open, 0),
+			COALESCE(ra.reassigned, 0),
+			a.avg_merge_seconds
+		FROM users u
+		LEFT JOIN (
+			SELECT rev.user_id,
+				COUNT(*) AS total,
+				COUNT(*) FILTER (WHERE pr.status = $1) AS open,
+				AVG((julianday(pr.merged_at) - julianday(rev.assigned_at)) * 86400) FILTER (WHERE pr.status = $2) AS avg_merge_seconds
+			FROM pr_reviewers rev
+			INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+			GROUP BY rev.user_id
+		) a ON a.user_id = u.user_id
+		LEFT JOIN (
+			SELECT old_user_id, COUNT(*) AS reassigned
+			FROM pr_reviewer_reassignments
+			GROUP BY old_user_id
+		) ra ON ra.old_user_id = u.user_id
+		LEFT JOIN (
+			SELECT user_id, COUNT(*) AS removed
+			FROM pr_events
+			WHERE action = $3
+			GROUP BY user_id
+		) rm ON rm.user_id = u.user_id`
+	args := []any{models.StatusOpen, models.StatusMerged, models.HistoryReviewerRemoved}
+
+	if teamName != "" {
+		query += ` WHERE u.team_name = $4`
+		args = append(args, teamName)
+	}
+	query += ` ORDER BY COALESCE(u.team_name, ''), u.user_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []models.ReviewerStats
+	for rows.Next() {
+		var st models.ReviewerStats
+		if err := rows.Scan(
+			&st.UserID, &st.Username, &st.TeamName,
+			&st.TotalAssignments, &st.OpenAssignments, &st.ReassignedAway, &st.AvgTimeToMergeSeconds,
+		); err != nil {
+			return nil, err
+		}
+		stats = append(stats, st)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetOpenReviewsOf returns every reviewer assignment on the OPEN pull requests
+// reviewed by any of the given users, including assignments of other reviewers.
+func (r *PRRepository) GetOpenReviewsOf(ctx context.Context, userIDs []string) ([]models.OpenReview, error) {
+	list, args := in([]any{models.StatusOpen}, userIDs)
+	query := `SELECT rev.pull_request_id, pr.author_id, rev.user_id
+		FROM pr_reviewers rev
+		INNER JOIN pull_requests pr ON pr.pull_request_id = rev.pull_request_id
+		WHERE pr.status = $1 AND rev.pull_request_id IN (
+			SELECT pull_request_id FROM pr_reviewers WHERE user_id IN ` + list + `
+		)
+		ORDER BY rev.pull_request_id, rev.user_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []models.OpenReview
+	for rows.Next() {
+		var review models.OpenReview
+		if err := rows.Scan(&review.PullRequestID, &review.AuthorID, &review.ReviewerID); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
+
+// HasOpenPullRequests reports whether any of the users authors or reviews an
+// OPEN pull request.
+func (r *PRRepository) HasOpenPullRequests(ctx context.Context, userIDs []string) (bool, error) {
+	authors, args := in([]any{models.StatusOpen}, userIDs)
+	reviewers, args := in(args, userIDs)
+	query := `SELECT EXISTS(
+		SELECT 1 FROM pull_requests pr
+		WHERE pr.status = $1 AND (
+			pr.author_id IN ` + authors + `
+			OR EXISTS(SELECT 1 FROM pr_reviewers rev WHERE rev.pull_request_id = pr.pull_request_id AND rev.user_id IN ` + reviewers + `)
+		)
+	)`
+
+	var exists bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&exists)
+	return exists, err
+}
+
+// GetUnderstaffed returns the OPEN pull requests with fewer reviewers than
+// required, oldest first; a non-empty teamName keeps the ones of its authors.
+func (r *PRRepository) GetUnderstaffed(ctx context.Context, teamName string) ([]string, error) {
+	query := `SELECT pr.pull_request_id FROM pull_requests pr
+		WHERE pr.status = $1
+			AND (SELECT COUNT(*) FROM pr_reviewers rev WHERE rev.pull_request_id = pr.pull_request_id) < pr.required_reviewers
+			AND ($2 = '' OR EXISTS(SELECT 1 FROM users u WHERE u.user_id = pr.author_id AND u.team_name = $2))
+		ORDER BY pr.created_at, pr.pull_request_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, models.StatusOpen, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prIDs []string
+	for rows.Next() {
+		var prID string
+		if err := rows.Scan(&prID); err != nil {
+			return nil, err
+		}
+		prIDs = append(prIDs, prID)
+	}
+
+	return prIDs, rows.Err()
+}
+
+// AddHistory appends entries to the history of their pull requests in the
+// given order with multi-row statements.
+func (r *PRRepository) AddHistory(ctx context.Context, entries []models.HistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	rows := make([][]any, len(entries))
+	for i, entry := range entries {
+		rows[i] = []any{
+			entry.PullRequestID, entry.Action, nullString(entry.UserID), nullString(entry.OldUserID),
+			nullString(entry.Status), nullString(entry.ReviewState), nullString(entry.Reason), entry.OccurredAt.UTC(),
+		}
+	}
+
+	query := `INSERT INTO pr_events (pull_request_id, action, user_id, old_user_id, status, review_state, reason, occurred_at)
+		VALUES %s`
+
+	_, err := execValues(ctx, conn(ctx, r.db), query, rows)
+	return err
+}
+
+// GetHistory returns the history of the pull request, oldest first.
+func (r *PRRepository) GetHistory(ctx context.Context, prID string) ([]models.HistoryEntry, error) {
+	query := `SELECT pull_request_id, action, COALESCE(user_id, ''), COALESCE(old_user_id, ''),
+			COALESCE(status, ''), COALESCE(review_state, ''), COALESCE(reason, ''), occurred_at
+		FROM pr_events
+		WHERE pull_request_id = $1
+		ORDER BY id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.HistoryEntry
+	for rows.Next() {
+		var entry models.HistoryEntry
+		if err := rows.Scan(
+			&entry.PullRequestID, &entry.Action, &entry.UserID, &entry.OldUserID,
+			&entry.Status, &entry.ReviewState, &entry.Reason, &entry.OccurredAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// nullString stores an empty string as NULL.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
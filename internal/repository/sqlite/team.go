@@ -0,0 +1,272 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"pr-reviewer-service/internal/models"
+)
+
+type TeamRepository struct {
+	db *sql.DB
+}
+
+func NewTeamRepository(db *sql.DB) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
+	query := `INSERT INTO teams (team_name, reviewer_strategy, reviewers_count) VALUES ($1, $2, $3)`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, team.TeamName, team.ReviewerStrategy, team.ReviewersCount)
+	return mapError(err)
+}
+
+func (r *TeamRepository) Exists(ctx context.Context, teamName string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)`
+
+	var exists bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, teamName).Scan(&exists)
+	return exists, err
+}
+
+func (r *TeamRepository) Get(ctx context.Context, teamName string) (*models.Team, error) {
+	settings, err := r.GetSettings(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return nil, nil
+	}
+
+	query := `SELECT user_id, username, is_active FROM users WHERE team_name = $1`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.TeamMember
+	for rows.Next() {
+		var member models.TeamMember
+		if err := rows.Scan(&member.UserID, &member.Username, &member.IsActive); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	team := &models.Team{
+		TeamName:         teamName,
+		ReviewerStrategy: settings.ReviewerStrategy,
+		ReviewersCount:   settings.ReviewersCount,
+		Members:          members,
+	}
+
+	return team, nil
+}
+
+func (r *TeamRepository) GetSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	query := `SELECT team_name, reviewer_strategy, reviewers_count, required_approvals FROM teams WHERE team_name = $1`
+
+	settings := &models.TeamSettings{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, teamName).Scan(
+		&settings.TeamName, &settings.ReviewerStrategy, &settings.ReviewersCount, &settings.RequiredApprovals,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fallbackQuery := `SELECT fallback_team_name FROM team_fallbacks WHERE team_name = $1 ORDER BY position`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, fallbackQuery, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settings.FallbackTeams = []string{}
+	for rows.Next() {
+		var fallbackTeam string
+		if err := rows.Scan(&fallbackTeam); err != nil {
+			return nil, err
+		}
+		settings.FallbackTeams = append(settings.FallbackTeams, fallbackTeam)
+	}
+
+	return settings, rows.Err()
+}
+
+// GetFallingBackTo returns the names of the teams that list teamName among
+// their fallback teams.
+func (r *TeamRepository) GetFallingBackTo(ctx context.Context, teamName string) ([]string, error) {
+	query := `SELECT team_name FROM team_fallbacks WHERE fallback_team_name = $1 ORDER BY team_name`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teamNames []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		teamNames = append(teamNames, name)
+	}
+
+	return teamNames, rows.Err()
+}
+
+// UpdateSettings stores the settings, replacing the fallback teams as a whole.
+func (r *TeamRepository) UpdateSettings(ctx context.Context, settings *models.TeamSettings) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	query := `UPDATE teams SET reviewer_strategy = $1, reviewers_count = $2, required_approvals = $3 WHERE team_name = $4`
+	if _, err := tx.ExecContext(ctx, query, settings.ReviewerStrategy, settings.ReviewersCount, settings.RequiredApprovals, settings.TeamName); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM team_fallbacks WHERE team_name = $1`, settings.TeamName); err != nil {
+		return err
+	}
+	for position, fallbackTeam := range settings.FallbackTeams {
+		fallbackQuery := `INSERT INTO team_fallbacks (team_name, fallback_team_name, position) VALUES ($1, $2, $3)`
+		if _, err := tx.ExecContext(ctx, fallbackQuery, settings.TeamName, fallbackTeam, position); err != nil {
+			return mapError(err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Rename moves the team, its members, webhooks and fallback links to newName.
+// Foreign keys do not cascade updates, so the team is copied under the new name
+// first.
+func (r *TeamRepository) Rename(ctx context.Context, teamName, newName string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	query := `INSERT INTO teams (team_name, reviewer_strategy, reviewers_count, required_approvals, created_at)
+		SELECT CAST($1 AS VARCHAR(255)), reviewer_strategy, reviewers_count, required_approvals, created_at FROM teams WHERE team_name = $2`
+
+	result, err := tx.ExecContext(ctx, query, newName, teamName)
+	if err != nil {
+		return mapError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	for _, query := range []string{
+		`UPDATE users SET team_name = $1, updated_at = CURRENT_TIMESTAMP WHERE team_name = $2`,
+		`UPDATE webhooks SET team_name = $1 WHERE team_name = $2`,
+		`UPDATE team_fallbacks SET team_name = $1 WHERE team_name = $2`,
+		`UPDATE team_fallbacks SET fallback_team_name = $1 WHERE fallback_team_name = $2`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, newName, teamName); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM teams WHERE team_name = $1`, teamName); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes the team with its webhooks and fallback links, also the ones
+// of teams falling back to it. Members stay as users without a team, keeping
+// their review history.
+func (r *TeamRepository) Delete(ctx context.Context, teamName string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	for _, query := range []string{
+		`UPDATE users SET team_name = NULL, updated_at = CURRENT_TIMESTAMP WHERE team_name = $1`,
+		`DELETE FROM webhooks WHERE team_name = $1`,
+		`DELETE FROM team_fallbacks WHERE team_name = $1 OR fallback_team_name = $1`,
+	} {
+		if _, err = tx.ExecContext(ctx, query, teamName); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM teams WHERE team_name = $1`, teamName)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// List returns up to filter.Limit teams ordered by name and starting after
+// filter.After, with the number of their members and active members.
+func (r *TeamRepository) List(ctx context.Context, filter *models.TeamFilter) ([]models.TeamSummary, error) {
+	query := `SELECT t.team_name, t.reviewer_strategy, t.reviewers_count,
+			COUNT(u.user_id), COUNT(u.user_id) FILTER (WHERE u.is_active)
+		FROM teams t
+		LEFT JOIN users u ON u.team_name = t.team_name
+		WHERE t.team_name > $1
+		GROUP BY t.team_name, t.reviewer_strategy, t.reviewers_count
+		ORDER BY t.team_name
+		LIMIT $2`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, filter.After, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []models.TeamSummary
+	for rows.Next() {
+		var team models.TeamSummary
+		if err := rows.Scan(
+			&team.TeamName, &team.ReviewerStrategy, &team.ReviewersCount,
+			&team.MembersCount, &team.ActiveMembersCount,
+		); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+
+	return teams, rows.Err()
+}
@@ -0,0 +1,89 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+)
+
+type txKey struct{}
+
+// TxManager runs units of work that span several repository calls in one
+// transaction. Repositories join the transaction carried by the context.
+type TxManager struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithinTx calls fn with a context that carries a new transaction and commits
+// it when fn succeeds. Nested calls join the outer transaction. The pool has a
+// single connection, so units of work run one at a time.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// executor is what *sql.DB and *sql.Tx have in common.
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn returns the transaction carried by ctx, or db outside of one.
+func conn(ctx context.Context, db *sql.DB) executor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// localTx is a transaction of a single repository method. Inside a unit of
+// work it wraps the outer transaction, and Commit and Rollback are left to
+// its owner.
+type localTx struct {
+	*sql.Tx
+	owned bool
+}
+
+func beginTx(ctx context.Context, db *sql.DB) (*localTx, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return &localTx{Tx: tx}, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &localTx{Tx: tx, owned: true}, nil
+}
+
+func (tx *localTx) Commit() error {
+	if !tx.owned {
+		return nil
+	}
+	return tx.Tx.Commit()
+}
+
+func (tx *localTx) Rollback() error {
+	if !tx.owned {
+		return nil
+	}
+	return tx.Tx.Rollback()
+}
@@ -0,0 +1,214 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"pr-reviewer-service/internal/models"
+)
+
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Create inserts the user or updates an existing one with the same user_id.
+// An empty team name leaves the user without a team.
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	query := `INSERT INTO users (user_id, username, team_name, is_active) 
+		VALUES ($1, $2, NULLIF($3, ''), $4)
+		ON CONFLICT (user_id) DO UPDATE 
+		SET username = $2, team_name = NULLIF($3, ''), is_active = $4, updated_at = CURRENT_TIMESTAMP`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, user.UserID, user.Username, user.TeamName, user.IsActive)
+	return err
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, userID string) (*models.User, error) {
+	query := `SELECT user_id, username, COALESCE(team_name, ''), is_active FROM users WHERE user_id = $1`
+
+	user := &models.User{}
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetByIDs returns the users that exist among userIDs.
+func (r *UserRepository) GetByIDs(ctx context.Context, userIDs []string) ([]models.User, error) {
+	list, args := in(nil, userIDs)
+	query := `SELECT user_id, username, COALESCE(team_name, ''), is_active FROM users WHERE user_id IN ` + list
+
+	return r.queryUsers(ctx, query, args...)
+}
+
+func (r *UserRepository) GetByTeam(ctx context.Context, teamName string) ([]models.User, error) {
+	query := `SELECT user_id, username, team_name, is_active FROM users WHERE team_name = $1`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *UserRepository) UpdateIsActive(ctx context.Context, userID string, isActive bool) error {
+	query := `UPDATE users SET is_active = $1, updated_at = CURRENT_TIMESTAMP WHERE user_id = $2`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, isActive, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateTeam moves the user to another team; an empty team name removes the
+// user from their team.
+func (r *UserRepository) UpdateTeam(ctx context.Context, userID, teamName string) error {
+	query := `UPDATE users SET team_name = NULLIF($1, ''), updated_at = CURRENT_TIMESTAMP WHERE user_id = $2`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, teamName, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *UserRepository) GetActiveTeamMembers(ctx context.Context, teamName, excludeUserID string) ([]models.User, error) {
+	query := `SELECT user_id, username, team_name, is_active 
+		FROM users 
+		WHERE team_name = $1 AND is_active = true AND user_id != $2`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamName, excludeUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// GetActiveMembersOfTeams returns the active members of any of the teams.
+func (r *UserRepository) GetActiveMembersOfTeams(ctx context.Context, teamNames []string) ([]models.User, error) {
+	list, args := in(nil, teamNames)
+	query := `SELECT user_id, username, team_name, is_active
+		FROM users
+		WHERE team_name IN ` + list + ` AND is_active = true`
+
+	return r.queryUsers(ctx, query, args...)
+}
+
+func (r *UserRepository) queryUsers(ctx context.Context, query string, args ...any) ([]models.User, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// List returns up to filter.Limit users matching the filter, ordered by user_id
+// and starting after filter.After.
+func (r *UserRepository) List(ctx context.Context, filter *models.UserFilter) ([]models.User, error) {
+	var conditions []string
+	var args []any
+
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	if filter.TeamName != "" {
+		addCondition(`team_name = ?`, filter.TeamName)
+	}
+	if filter.IsActive != nil {
+		addCondition(`is_active = ?`, *filter.IsActive)
+	}
+	if filter.After != "" {
+		addCondition(`user_id > ?`, filter.After)
+	}
+
+	query := `SELECT user_id, username, COALESCE(team_name, ''), is_active FROM users`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(` ORDER BY user_id LIMIT $%d`, len(args))
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
@@ -0,0 +1,157 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	query := `INSERT INTO webhooks (team_name, url, secret, created_at) VALUES ($1, $2, $3, $4) RETURNING id`
+
+	now := time.Now().UTC()
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, webhook.TeamName, webhook.URL, webhook.Secret, now).Scan(&webhook.ID); err != nil {
+		return err
+	}
+
+	webhook.CreatedAt = &now
+	return nil
+}
+
+func (r *WebhookRepository) GetByTeam(ctx context.Context, teamName string) ([]models.Webhook, error) {
+	query := `SELECT id, team_name, url, created_at FROM webhooks WHERE team_name = $1 ORDER BY id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []models.Webhook
+	for rows.Next() {
+		var webhook models.Webhook
+		if err := rows.Scan(&webhook.ID, &webhook.TeamName, &webhook.URL, &webhook.CreatedAt); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM webhooks WHERE id = $1`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Enqueue stores a pending delivery of the event for every webhook of its team.
+// Enqueueing the same event again is a no-op.
+func (r *WebhookRepository) Enqueue(ctx context.Context, event *models.Event, payload []byte) error {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+		SELECT id, $1, $2, $3 FROM webhooks WHERE team_name = $4
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, event.ID, event.Type, string(payload), event.TeamName)
+	return err
+}
+
+// ClaimDue returns up to limit pending deliveries that are due and pushes their
+// next attempt forward by lease. SQLite allows a single writer, so claiming in
+// a transaction is enough to keep other dispatchers away.
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback() // nolint:errcheck // rollback is safe to ignore in defer
+	}()
+
+	query := `SELECT d.id, d.event_type, d.payload, d.attempts, w.url, w.secret
+		FROM webhook_deliveries d
+		INNER JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = $1 AND d.next_attempt_at <= $2
+		ORDER BY d.next_attempt_at, d.id
+		LIMIT $3`
+
+	rows, err := tx.QueryContext(ctx, query, models.DeliveryPending, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		var payload string
+		if err := rows.Scan(
+			&delivery.ID, &delivery.EventType, &payload, &delivery.Attempts, &delivery.URL, &delivery.Secret,
+		); err != nil {
+			return nil, err
+		}
+		delivery.Payload = []byte(payload)
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(deliveries))
+	for i, delivery := range deliveries {
+		ids[i] = delivery.ID
+	}
+
+	if len(ids) > 0 {
+		list, args := in([]any{now.Add(lease).UTC()}, ids)
+		if _, err := tx.ExecContext(ctx, `UPDATE webhook_deliveries SET next_attempt_at = $1 WHERE id IN `+list, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	return deliveries, tx.Commit()
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id int64, attempts int) error {
+	query := `UPDATE webhook_deliveries SET status = $1, attempts = $2, delivered_at = $3, last_error = NULL WHERE id = $4`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, models.DeliveryDelivered, attempts, time.Now().UTC(), id)
+	return err
+}
+
+// MarkRetry records a failed attempt and schedules the next one.
+func (r *WebhookRepository) MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	query := `UPDATE webhook_deliveries SET attempts = $1, next_attempt_at = $2, last_error = $3 WHERE id = $4`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, attempts, nextAttemptAt.UTC(), lastError, id)
+	return err
+}
+
+// MarkFailed gives up on a delivery after the last allowed attempt.
+func (r *WebhookRepository) MarkFailed(ctx context.Context, id int64, attempts int, lastError string) error {
+	query := `UPDATE webhook_deliveries SET status = $1, attempts = $2, last_error = $3 WHERE id = $4`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, models.DeliveryFailed, attempts, lastError, id)
+	return err
+}
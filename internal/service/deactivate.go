@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// DeactivateUsers deactivates a set of team members and hands each of their
+// reviews on OPEN pull requests over as ReassignReviewer would: to a teammate,
+// or else to a member of the author's team or its fallback teams. Reviews nobody
+// can take over stay in place and are reported as unreassigned. The affected
+// pull requests stay locked until the whole unit of work commits.
+func (s *Service) DeactivateUsers(ctx context.Context, teamName string, userIDs []string) (*models.DeactivationResult, error) {
+	var result *models.DeactivationResult
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.deactivateUsers(ctx, teamName, userIDs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *Service) deactivateUsers(ctx context.Context, teamName string, userIDs []string) (*models.DeactivationResult, error) {
+	members, err := s.userRepo.GetByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		exists, existsErr := s.teamRepo.Exists(ctx, teamName)
+		if existsErr != nil {
+			return nil, existsErr
+		}
+		if !exists {
+			return nil, ErrNotFound
+		}
+	}
+
+	isMember := make(map[string]struct{}, len(members))
+	for _, member := range members {
+		isMember[member.UserID] = struct{}{}
+	}
+
+	deactivatedIDs := slices.Clone(userIDs)
+	sort.Strings(deactivatedIDs)
+	deactivatedIDs = slices.Compact(deactivatedIDs)
+	for _, userID := range deactivatedIDs {
+		if _, ok := isMember[userID]; !ok {
+			return nil, ErrNotFound
+		}
+	}
+
+	// nobody being deactivated may take over a review of another one
+	for _, userID := range deactivatedIDs {
+		if err = s.userRepo.UpdateIsActive(ctx, userID, false); err != nil {
+			return nil, err
+		}
+	}
+
+	reassigned, unreassigned, err := s.reassignReviewsOf(ctx, deactivatedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DeactivationResult{
+		DeactivatedUsers: deactivatedIDs,
+		Reassigned:       reassigned,
+		Unreassigned:     unreassigned,
+	}, nil
+}
+
+// reassignReviewsOf hands every OPEN review of the users over to a replacement
+// picked the way ReassignReviewer picks one. The users must already be
+// inactive, so that they are never picked themselves. Reviews nobody can take
+// over are returned as unreassigned.
+//
+// The whole batch is planned in memory from one read of the pull requests,
+// the involved users, the candidates and their loads, and is then written with
+// one ReplaceReviewers and one AddHistory call.
+func (s *Service) reassignReviewsOf(ctx context.Context, userIDs []string) ([]models.ReviewerReplacement, []models.UnreassignedReview, error) {
+	reassigned := []models.ReviewerReplacement{}
+	unreassigned := []models.UnreassignedReview{}
+
+	reviews, err := s.lockOpenReviewsOf(ctx, userIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var prIDs []string
+	for _, review := range reviews {
+		if slices.Contains(userIDs, review.ReviewerID) && !slices.Contains(prIDs, review.PullRequestID) {
+			prIDs = append(prIDs, review.PullRequestID)
+		}
+	}
+	if len(prIDs) == 0 {
+		return reassigned, unreassigned, nil
+	}
+
+	prs, err := s.prRepo.GetByIDs(ctx, prIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plan, err := s.newReassignmentPlan(ctx, userIDs, prs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	var (
+		events  []*models.Event
+		history []models.HistoryEntry
+	)
+	for i := range prs {
+		pr := &prs[i]
+		authorTeam := plan.teams[pr.AuthorID]
+
+		// reviewers are walked in user_id order so that the plan does not
+		// depend on the order they were assigned in
+		oldReviewerIDs := slices.Sorted(slices.Values(pr.AssignedReviewers))
+		for _, oldReviewerID := range oldReviewerIDs {
+			if !slices.Contains(userIDs, oldReviewerID) {
+				continue
+			}
+
+			newReviewerID, fromFallback, pickErr := plan.pick(ctx, pr, plan.teams[oldReviewerID])
+			if pickErr != nil {
+				return nil, nil, pickErr
+			}
+			if newReviewerID == "" {
+				unreassigned = append(unreassigned, models.UnreassignedReview{
+					PullRequestID: pr.PullRequestID,
+					UserID:        oldReviewerID,
+				})
+				continue
+			}
+
+			swapReviewer(pr, oldReviewerID, newReviewerID, fromFallback)
+			snapshot := *pr
+			snapshot.Reviewers = nil
+			snapshot.AssignedReviewers = slices.Clone(pr.AssignedReviewers)
+			snapshot.FallbackReviewers = slices.Clone(pr.FallbackReviewers)
+
+			event, eventErr := newEvent(models.EventReviewerReassigned, authorTeam, pr.PullRequestID, now, models.ReviewerReassignedData{
+				PullRequest: &snapshot,
+				OldUserID:   oldReviewerID,
+				NewUserID:   newReviewerID,
+			})
+			if eventErr != nil {
+				return nil, nil, eventErr
+			}
+
+			reassigned = append(reassigned, models.ReviewerReplacement{
+				PullRequestID: pr.PullRequestID,
+				OldUserID:     oldReviewerID,
+				NewUserID:     newReviewerID,
+				FromFallback:  fromFallback,
+			})
+			events = append(events, event)
+			history = append(history, reassignEntry(pr.PullRequestID, oldReviewerID, newReviewerID, models.ReasonDeactivated, now))
+		}
+	}
+
+	if err = s.prRepo.ReplaceReviewers(ctx, reassigned, events); err != nil {
+		return nil, nil, err
+	}
+	if err = s.prRepo.AddHistory(ctx, history); err != nil {
+		return nil, nil, err
+	}
+
+	return reassigned, unreassigned, nil
+}
+
+// reassignmentPlan holds what picking replacements for a batch of pull
+// requests needs, so that the picks themselves make no storage calls.
+type reassignmentPlan struct {
+	// teams maps the authors and the old reviewers to their teams
+	teams     map[string]string
+	settings  map[string]*models.TeamSettings
+	members   map[string][]models.User
+	selectors map[string]ReviewerSelector
+	loads     plannedLoads
+}
+
+// newReassignmentPlan loads the teams of the authors of prs and of the users,
+// the settings of those teams and their fallback teams, and the active members
+// of all of them together with their open review counts.
+func (s *Service) newReassignmentPlan(ctx context.Context, userIDs []string, prs []models.PullRequest) (*reassignmentPlan, error) {
+	personIDs := slices.Clone(userIDs)
+	for _, pr := range prs {
+		personIDs = append(personIDs, pr.AuthorID)
+	}
+	people, err := s.userRepo.GetByIDs(ctx, personIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &reassignmentPlan{
+		teams:    make(map[string]string, len(people)),
+		settings: map[string]*models.TeamSettings{},
+		members:  map[string][]models.User{},
+		loads:    plannedLoads{},
+	}
+	for _, person := range people {
+		plan.teams[person.UserID] = person.TeamName
+	}
+
+	var teamNames []string
+	addTeam := func(teamName string) error {
+		if _, ok := plan.settings[teamName]; ok || teamName == "" {
+			return nil
+		}
+		settings, settingsErr := s.getTeamSettings(ctx, teamName)
+		if settingsErr != nil {
+			return settingsErr
+		}
+		plan.settings[teamName] = settings
+		teamNames = append(teamNames, teamName)
+		return nil
+	}
+	for _, teamName := range plan.teams {
+		if err = addTeam(teamName); err != nil {
+			return nil, err
+		}
+	}
+	for _, pr := range prs {
+		settings, ok := plan.settings[plan.teams[pr.AuthorID]]
+		if !ok {
+			continue
+		}
+		for _, fallbackTeam := range settings.FallbackTeams {
+			if err = addTeam(fallbackTeam); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	members, err := s.userRepo.GetActiveMembersOfTeams(ctx, teamNames)
+	if err != nil {
+		return nil, err
+	}
+	memberIDs := make([]string, len(members))
+	for i, member := range members {
+		plan.members[member.TeamName] = append(plan.members[member.TeamName], member)
+		memberIDs[i] = member.UserID
+	}
+
+	if len(memberIDs) > 0 {
+		plan.loads, err = s.prRepo.CountOpenReviews(ctx, memberIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// least_loaded reads the loads kept up to date by the plan instead of
+	// counting them again for every pick
+	plan.selectors = maps.Clone(s.selectors)
+	plan.selectors[models.StrategyLeastLoaded] = NewLeastLoadedSelector(plan.loads)
+
+	return plan, nil
+}
+
+// pick chooses a replacement on pr for a reviewer of oldTeam the way
+// replaceReviewer does: from oldTeam, else from the author's team and its
+// fallback teams, never the author or a reviewer already assigned. It returns
+// an empty user_id when nobody can take over.
+func (p *reassignmentPlan) pick(ctx context.Context, pr *models.PullRequest, oldTeam string) (string, bool, error) {
+	authorTeam := p.teams[pr.AuthorID]
+
+	teamNames := []string{oldTeam, authorTeam}
+	if settings, ok := p.settings[authorTeam]; ok {
+		teamNames = append(teamNames, settings.FallbackTeams...)
+	}
+	excludeIDs := append(slices.Clone(pr.AssignedReviewers), pr.AuthorID)
+
+	asked := make(map[string]struct{}, len(teamNames))
+	for _, teamName := range teamNames {
+		if _, ok := asked[teamName]; ok || teamName == "" {
+			continue
+		}
+		asked[teamName] = struct{}{}
+
+		candidates := excludeUsers(p.members[teamName], excludeIDs)
+		if len(candidates) == 0 {
+			continue
+		}
+
+		settings := p.settings[teamName]
+		selected, err := p.selectors[settings.ReviewerStrategy].Select(ctx, teamName, candidates, 1)
+		if err != nil {
+			return "", false, err
+		}
+		if len(selected) > 0 {
+			p.loads[selected[0]]++
+			return selected[0], teamName != authorTeam, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// plannedLoads counts open reviews in memory while a batch of replacements is
+// planned.
+type plannedLoads map[string]int
+
+func (l plannedLoads) CountOpenReviews(context.Context, []string) (map[string]int, error) {
+	return l, nil
+}
+
+// swapReviewer replaces oldReviewerID with newReviewerID in the reviewer lists of pr.
+func swapReviewer(pr *models.PullRequest, oldReviewerID, newReviewerID string, fromFallback bool) {
+	for i, reviewerID := range pr.AssignedReviewers {
+		if reviewerID == oldReviewerID {
+			pr.AssignedReviewers[i] = newReviewerID
+		}
+	}
+	pr.FallbackReviewers = slices.DeleteFunc(pr.FallbackReviewers, func(reviewerID string) bool {
+		return reviewerID == oldReviewerID
+	})
+	if fromFallback {
+		pr.FallbackReviewers = append(pr.FallbackReviewers, newReviewerID)
+	}
+}
+
+// lockOpenReviewsOf locks every OPEN pull request reviewed by any of the users
+// and returns its reviewer assignments as read under the locks. Pull requests
+// that gained one of the users while the locks were taken are locked too.
+func (s *Service) lockOpenReviewsOf(ctx context.Context, userIDs []string) ([]models.OpenReview, error) {
+	locked := map[string]struct{}{}
+	for {
+		reviews, err := s.prRepo.GetOpenReviewsOf(ctx, userIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		var unlocked []string
+		for _, review := range reviews {
+			if _, ok := locked[review.PullRequestID]; !ok && !slices.Contains(unlocked, review.PullRequestID) {
+				unlocked = append(unlocked, review.PullRequestID)
+			}
+		}
+		if len(unlocked) == 0 {
+			return reviews, nil
+		}
+
+		if err = s.prRepo.LockAll(ctx, unlocked); err != nil {
+			return nil, err
+		}
+		for _, prID := range unlocked {
+			locked[prID] = struct{}{}
+		}
+	}
+}
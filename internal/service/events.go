@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// newEvent builds a domain event for the outbox. The repository stores it in
+// the same transaction as the change it describes.
+func newEvent(eventType, teamName, prID string, occurredAt time.Time, data any) (*models.Event, error) {
+	eventID, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Event{
+		ID:            eventID,
+		Type:          eventType,
+		TeamName:      teamName,
+		PullRequestID: prID,
+		OccurredAt:    occurredAt.UTC(),
+		Data:          rawData,
+	}, nil
+}
+
+// authorTeam returns the team whose webhooks receive events of the author's pull requests.
+func (s *Service) authorTeam(ctx context.Context, authorID string) (string, error) {
+	author, err := s.userRepo.GetByID(ctx, authorID)
+	if err != nil {
+		return "", err
+	}
+	if author == nil {
+		return "", ErrNotFound
+	}
+	return author.TeamName, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// GetPullRequestHistory returns the audit trail of the pull request, oldest
+// first: its creation, status changes, reviewer assignments, replacements and
+// removals with their reasons, and submitted reviews.
+func (s *Service) GetPullRequestHistory(ctx context.Context, prID string) ([]models.HistoryEntry, error) {
+	exists, err := s.prRepo.Exists(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	entries, err := s.prRepo.GetHistory(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries == nil {
+		entries = []models.HistoryEntry{}
+	}
+
+	return entries, nil
+}
+
+// reviewerEntries builds one history entry per reviewer for the given action
+// and reason.
+func reviewerEntries(prID, action, reason string, userIDs []string, occurredAt time.Time) []models.HistoryEntry {
+	entries := make([]models.HistoryEntry, 0, len(userIDs))
+	for _, userID := range userIDs {
+		entries = append(entries, models.HistoryEntry{
+			OccurredAt:    occurredAt,
+			PullRequestID: prID,
+			Action:        action,
+			UserID:        userID,
+			Reason:        reason,
+		})
+	}
+	return entries
+}
+
+// statusEntry builds the history entry of a pull request created in or moved
+// to status.
+func statusEntry(prID, action, status, reason string, occurredAt time.Time) models.HistoryEntry {
+	return models.HistoryEntry{
+		OccurredAt:    occurredAt,
+		PullRequestID: prID,
+		Action:        action,
+		Status:        status,
+		Reason:        reason,
+	}
+}
+
+// reassignEntry builds the history entry of a reviewer replaced by another.
+func reassignEntry(prID, oldUserID, newUserID, reason string, occurredAt time.Time) models.HistoryEntry {
+	return models.HistoryEntry{
+		OccurredAt:    occurredAt,
+		PullRequestID: prID,
+		Action:        models.HistoryReviewerReassigned,
+		UserID:        newUserID,
+		OldUserID:     oldUserID,
+		Reason:        reason,
+	}
+}
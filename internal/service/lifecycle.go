@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// transitions lists the statuses a pull request can move to from each status.
+// MERGED is final.
+var transitions = map[string][]string{
+	models.StatusDraft:  {models.StatusOpen, models.StatusClosed},
+	models.StatusOpen:   {models.StatusMerged, models.StatusClosed},
+	models.StatusClosed: {models.StatusOpen},
+}
+
+// TransitionError reports a status change the lifecycle does not allow. It
+// matches ErrInvalidTransition with errors.Is.
+type TransitionError struct {
+	From string
+	To   string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot move PR from %s to %s", e.From, e.To)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+func checkTransition(from, to string) error {
+	if !slices.Contains(transitions[from], to) {
+		return &TransitionError{From: from, To: to}
+	}
+	return nil
+}
+
+// MarkReady moves a DRAFT pull request to OPEN and assigns its reviewers.
+func (s *Service) MarkReady(ctx context.Context, prID string) (*models.PullRequest, error) {
+	return s.transition(ctx, prID, models.StatusDraft, models.StatusOpen, models.EventPullRequestReady)
+}
+
+// ClosePullRequest abandons a DRAFT or OPEN pull request without merging it
+// and releases its reviewers.
+func (s *Service) ClosePullRequest(ctx context.Context, prID string) (*models.PullRequest, error) {
+	return s.transition(ctx, prID, "", models.StatusClosed, models.EventPullRequestClosed)
+}
+
+// ReopenPullRequest moves a CLOSED pull request back to OPEN and assigns
+// reviewers afresh.
+func (s *Service) ReopenPullRequest(ctx context.Context, prID string) (*models.PullRequest, error) {
+	return s.transition(ctx, prID, models.StatusClosed, models.StatusOpen, models.EventPullRequestReopened)
+}
+
+// transition moves the pull request to status to. A non-empty from also
+// requires the pull request to be in that status, so that ready and reopen
+// cannot stand in for each other.
+func (s *Service) transition(ctx context.Context, prID, from, to, eventType string) (*models.PullRequest, error) {
+	var pr *models.PullRequest
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = s.changeStatus(ctx, prID, from, to, eventType)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+func (s *Service) changeStatus(ctx context.Context, prID, from, to, eventType string) (*models.PullRequest, error) {
+	exists, err := s.prRepo.Lock(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, ErrNotFound
+	}
+
+	if from != "" && pr.Status != from {
+		return nil, &TransitionError{From: pr.Status, To: to}
+	}
+	if err = checkTransition(pr.Status, to); err != nil {
+		return nil, err
+	}
+
+	teamName, err := s.authorTeam(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	changed := *pr
+	changed.Status = to
+	changed.Reviewers = nil
+
+	var reviewerHistory []models.HistoryEntry
+	switch to {
+	case models.StatusOpen:
+		settings, settingsErr := s.getTeamSettings(ctx, teamName)
+		if settingsErr != nil {
+			return nil, settingsErr
+		}
+
+		teamNames := append([]string{teamName}, settings.FallbackTeams...)
+		reviewers, fallbackReviewers, pickErr := s.pickReviewers(ctx, teamName, teamNames, []string{pr.AuthorID}, pr.RequiredReviewers)
+		if pickErr != nil {
+			return nil, pickErr
+		}
+		if len(reviewers) > 0 {
+			if err = s.prRepo.AddReviewers(ctx, prID, reviewers, fallbackReviewers, nil); err != nil {
+				return nil, err
+			}
+		}
+		changed.AssignedReviewers = reviewers
+		changed.FallbackReviewers = fallbackReviewers
+		reviewerHistory = reviewerEntries(prID, models.HistoryReviewerAssigned, models.ReasonAuto, reviewers, now)
+	case models.StatusClosed:
+		for _, reviewerID := range pr.AssignedReviewers {
+			if err = s.prRepo.RemoveReviewer(ctx, prID, reviewerID, nil); err != nil {
+				return nil, err
+			}
+		}
+		changed.AssignedReviewers = []string{}
+		changed.FallbackReviewers = nil
+		reviewerHistory = reviewerEntries(prID, models.HistoryReviewerRemoved, models.ReasonClosed, pr.AssignedReviewers, now)
+	}
+
+	event, err := newEvent(eventType, teamName, prID, now, models.PullRequestStatusData{
+		PullRequest:    &changed,
+		PreviousStatus: pr.Status,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.prRepo.UpdateStatus(ctx, prID, to, now, event); err != nil {
+		return nil, err
+	}
+
+	history := append([]models.HistoryEntry{statusEntry(prID, models.HistoryStatusChanged, to, "", now)}, reviewerHistory...)
+	if err := s.prRepo.AddHistory(ctx, history); err != nil {
+		return nil, err
+	}
+
+	return s.prRepo.GetByID(ctx, prID)
+}
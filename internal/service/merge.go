@@ -0,0 +1,60 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// MergeBlockedError explains why a pull request does not pass the approval
+// gate of its team. It matches ErrMergeBlocked with errors.Is.
+type MergeBlockedError struct {
+	Approvals          int
+	RequiredApprovals  int
+	ChangesRequestedBy []string
+}
+
+func (e *MergeBlockedError) Error() string {
+	var reasons []string
+	if e.Approvals < e.RequiredApprovals {
+		reasons = append(reasons, fmt.Sprintf("%d of %d required approvals", e.Approvals, e.RequiredApprovals))
+	}
+	if len(e.ChangesRequestedBy) > 0 {
+		reasons = append(reasons, "changes requested by "+strings.Join(e.ChangesRequestedBy, ", "))
+	}
+	return "merge blocked: " + strings.Join(reasons, "; ")
+}
+
+func (e *MergeBlockedError) Is(target error) bool {
+	return target == ErrMergeBlocked
+}
+
+// checkMergeGate requires the team's number of approvals and no outstanding
+// CHANGES_REQUESTED from the current reviewers. A team without required
+// approvals has no gate. A pull request with fewer reviewers than required
+// approvals fails with ErrNotEnoughReviewers, as no number of reviews would
+// let it through.
+func checkMergeGate(pr *models.PullRequest, settings *models.TeamSettings) error {
+	if settings.RequiredApprovals == 0 {
+		return nil
+	}
+	if len(pr.Reviewers) < settings.RequiredApprovals {
+		return ErrNotEnoughReviewers
+	}
+
+	blocked := &MergeBlockedError{RequiredApprovals: settings.RequiredApprovals}
+	for _, reviewer := range pr.Reviewers {
+		switch reviewer.ReviewState {
+		case models.ReviewApproved:
+			blocked.Approvals++
+		case models.ReviewChangesRequested:
+			blocked.ChangesRequestedBy = append(blocked.ChangesRequestedBy, reviewer.UserID)
+		}
+	}
+
+	if blocked.Approvals >= blocked.RequiredApprovals && len(blocked.ChangesRequestedBy) == 0 {
+		return nil
+	}
+	return blocked
+}
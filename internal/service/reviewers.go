@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// AddReviewer assigns a named reviewer to an OPEN pull request on top of the
+// current ones. The pull request then requires at least as many reviewers as
+// it has, so a top-up never undoes the addition.
+func (s *Service) AddReviewer(ctx context.Context, prID, userID string) (*models.PullRequest, error) {
+	var pr *models.PullRequest
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = s.addReviewer(ctx, prID, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+func (s *Service) addReviewer(ctx context.Context, prID, userID string) (*models.PullRequest, error) {
+	pr, err := s.lockOpenPullRequest(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	teamName, err := s.authorTeam(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.getTeamSettings(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	fromFallback, err := s.checkReviewer(ctx, pr, settings, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pr.AssignedReviewers) >= MaxReviewersCount {
+		return nil, ErrInvalidReviewersCount
+	}
+
+	var fallbackIDs []string
+	if fromFallback {
+		fallbackIDs = []string{userID}
+	}
+
+	added := *pr
+	added.Reviewers = nil
+	added.AssignedReviewers = append(slices.Clone(pr.AssignedReviewers), userID)
+	added.FallbackReviewers = slices.Concat(pr.FallbackReviewers, fallbackIDs)
+	added.RequiredReviewers = max(pr.RequiredReviewers, len(added.AssignedReviewers))
+
+	now := time.Now()
+	event, err := newEvent(models.EventReviewersAdded, teamName, prID, now, models.ReviewersAddedData{
+		PullRequest: &added,
+		UserIDs:     []string{userID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.prRepo.AddReviewers(ctx, prID, []string{userID}, fallbackIDs, event); err != nil {
+		return nil, err
+	}
+	if added.RequiredReviewers != pr.RequiredReviewers {
+		if err := s.prRepo.UpdateRequiredReviewers(ctx, prID, added.RequiredReviewers); err != nil {
+			return nil, err
+		}
+	}
+
+	history := reviewerEntries(prID, models.HistoryReviewerAssigned, models.ReasonManual, []string{userID}, now)
+	if err := s.prRepo.AddHistory(ctx, history); err != nil {
+		return nil, err
+	}
+
+	return s.prRepo.GetByID(ctx, prID)
+}
+
+// RemoveReviewer unassigns a reviewer from an OPEN pull request without a
+// replacement. The number of required reviewers stays the same, so the next
+// top-up assigns someone else in their place.
+func (s *Service) RemoveReviewer(ctx context.Context, prID, userID string) (*models.PullRequest, error) {
+	var pr *models.PullRequest
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = s.removeReviewer(ctx, prID, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+func (s *Service) removeReviewer(ctx context.Context, prID, userID string) (*models.PullRequest, error) {
+	pr, err := s.lockOpenPullRequest(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(pr.AssignedReviewers, userID) {
+		return nil, ErrNotAssigned
+	}
+
+	teamName, err := s.authorTeam(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	isRemoved := func(reviewerID string) bool { return reviewerID == userID }
+	removed := *pr
+	removed.Reviewers = nil
+	removed.AssignedReviewers = slices.DeleteFunc(slices.Clone(pr.AssignedReviewers), isRemoved)
+	removed.FallbackReviewers = slices.DeleteFunc(slices.Clone(pr.FallbackReviewers), isRemoved)
+
+	now := time.Now()
+	event, err := newEvent(models.EventReviewerRemoved, teamName, prID, now, models.ReviewerRemovedData{
+		PullRequest: &removed,
+		UserID:      userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.prRepo.RemoveReviewer(ctx, prID, userID, event); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotAssigned
+		}
+		return nil, err
+	}
+
+	history := reviewerEntries(prID, models.HistoryReviewerRemoved, models.ReasonManual, []string{userID}, now)
+	if err := s.prRepo.AddHistory(ctx, history); err != nil {
+		return nil, err
+	}
+
+	return s.prRepo.GetByID(ctx, prID)
+}
+
+// SubmitReview records the decision of an assigned reviewer on an OPEN pull
+// request: APPROVED, CHANGES_REQUESTED or COMMENTED. A later decision replaces
+// the earlier one.
+func (s *Service) SubmitReview(ctx context.Context, prID, userID, state string) (*models.PullRequest, error) {
+	switch state {
+	case models.ReviewApproved, models.ReviewChangesRequested, models.ReviewCommented:
+	default:
+		return nil, ErrInvalidReviewState
+	}
+
+	var pr *models.PullRequest
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = s.submitReview(ctx, prID, userID, state)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+func (s *Service) submitReview(ctx context.Context, prID, userID, state string) (*models.PullRequest, error) {
+	pr, err := s.lockOpenPullRequest(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(pr.AssignedReviewers, userID) {
+		return nil, ErrNotAssigned
+	}
+
+	teamName, err := s.authorTeam(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reviewed := *pr
+	reviewed.Reviewers = slices.Clone(pr.Reviewers)
+	for i := range reviewed.Reviewers {
+		if reviewed.Reviewers[i].UserID == userID {
+			reviewed.Reviewers[i].ReviewState = state
+			reviewed.Reviewers[i].ReviewedAt = &now
+		}
+	}
+
+	event, err := newEvent(models.EventReviewSubmitted, teamName, prID, now, models.ReviewSubmittedData{
+		PullRequest: &reviewed,
+		UserID:      userID,
+		ReviewState: state,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.prRepo.SubmitReview(ctx, prID, userID, state, now, event); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotAssigned
+		}
+		return nil, err
+	}
+
+	history := []models.HistoryEntry{{
+		OccurredAt:    now,
+		PullRequestID: prID,
+		Action:        models.HistoryReviewSubmitted,
+		UserID:        userID,
+		ReviewState:   state,
+	}}
+	if err := s.prRepo.AddHistory(ctx, history); err != nil {
+		return nil, err
+	}
+
+	return s.prRepo.GetByID(ctx, prID)
+}
+
+// lockOpenPullRequest locks the pull request for the rest of the unit of work
+// and returns it, failing when it does not exist or is not OPEN.
+func (s *Service) lockOpenPullRequest(ctx context.Context, prID string) (*models.PullRequest, error) {
+	exists, err := s.prRepo.Lock(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, ErrNotFound
+	}
+
+	if pr.Status == models.StatusMerged {
+		return nil, ErrPRMerged
+	}
+	if pr.Status != models.StatusOpen {
+		return nil, ErrPRNotOpen
+	}
+
+	return pr, nil
+}
+
+// checkReviewer validates a reviewer picked by hand for the pull request: an
+// active member of the author's team or one of its fallback teams, other than
+// the author, not assigned yet. It reports whether the reviewer comes from a
+// fallback team.
+func (s *Service) checkReviewer(ctx context.Context, pr *models.PullRequest, settings *models.TeamSettings, userID string) (bool, error) {
+	if userID == pr.AuthorID {
+		return false, ErrReviewerIsAuthor
+	}
+	if slices.Contains(pr.AssignedReviewers, userID) {
+		return false, ErrAlreadyAssigned
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if user == nil {
+		return false, ErrNotFound
+	}
+	if !user.IsActive {
+		return false, ErrReviewerInactive
+	}
+
+	if user.TeamName == "" {
+		return false, ErrNotTeamMember
+	}
+	if user.TeamName == settings.TeamName {
+		return false, nil
+	}
+	if !slices.Contains(settings.FallbackTeams, user.TeamName) {
+		return false, ErrNotTeamMember
+	}
+
+	return true, nil
+}
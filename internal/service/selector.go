@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// ReviewerSelector picks up to n reviewers out of candidates for a team.
+type ReviewerSelector interface {
+	Select(ctx context.Context, teamName string, candidates []models.User, n int) ([]string, error)
+}
+
+// OpenReviewCounter reports how many OPEN pull requests each user reviews.
+type OpenReviewCounter interface {
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
+}
+
+type randomSelector struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewRandomSelector returns a selector that picks reviewers uniformly at random.
+func NewRandomSelector() ReviewerSelector {
+	return &randomSelector{
+		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (s *randomSelector) Select(_ context.Context, _ string, candidates []models.User, n int) ([]string, error) {
+	shuffled := make([]models.User, len(candidates))
+	copy(shuffled, candidates)
+
+	s.mu.Lock()
+	s.rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	s.mu.Unlock()
+
+	return firstUserIDs(shuffled, n), nil
+}
+
+type roundRobinSelector struct {
+	mu   sync.Mutex
+	last map[string]string
+}
+
+// NewRoundRobinSelector returns a selector that walks team members in
+// user_id order, continuing after the last reviewer it picked for the team.
+func NewRoundRobinSelector() ReviewerSelector {
+	return &roundRobinSelector{
+		last: make(map[string]string),
+	}
+}
+
+func (s *roundRobinSelector) Select(_ context.Context, teamName string, candidates []models.User, n int) ([]string, error) {
+	if len(candidates) == 0 {
+		return []string{}, nil
+	}
+
+	sorted := sortedByUserID(candidates)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := sort.Search(len(sorted), func(i int) bool {
+		return sorted[i].UserID > s.last[teamName]
+	})
+
+	rotated := make([]models.User, 0, len(sorted))
+	rotated = append(rotated, sorted[start%len(sorted):]...)
+	rotated = append(rotated, sorted[:start%len(sorted)]...)
+
+	reviewers := firstUserIDs(rotated, n)
+	if len(reviewers) > 0 {
+		s.last[teamName] = reviewers[len(reviewers)-1]
+	}
+
+	return reviewers, nil
+}
+
+type leastLoadedSelector struct {
+	counter OpenReviewCounter
+}
+
+// NewLeastLoadedSelector returns a selector that prefers candidates with the
+// fewest OPEN reviews. Ties are broken by user_id so the result is stable for
+// the same workload.
+func NewLeastLoadedSelector(counter OpenReviewCounter) ReviewerSelector {
+	return &leastLoadedSelector{
+		counter: counter,
+	}
+}
+
+func (s *leastLoadedSelector) Select(ctx context.Context, _ string, candidates []models.User, n int) ([]string, error) {
+	if len(candidates) == 0 {
+		return []string{}, nil
+	}
+
+	sorted := sortedByUserID(candidates)
+
+	userIDs := make([]string, len(sorted))
+	for i, candidate := range sorted {
+		userIDs[i] = candidate.UserID
+	}
+
+	loads, err := s.counter.CountOpenReviews(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return loads[sorted[i].UserID] < loads[sorted[j].UserID]
+	})
+
+	return firstUserIDs(sorted, n), nil
+}
+
+func sortedByUserID(users []models.User) []models.User {
+	sorted := make([]models.User, len(users))
+	copy(sorted, users)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].UserID < sorted[j].UserID
+	})
+	return sorted
+}
+
+func firstUserIDs(users []models.User, n int) []string {
+	if n > len(users) {
+		n = len(users)
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	userIDs := make([]string, n)
+	for i := 0; i < n; i++ {
+		userIDs[i] = users[i].UserID
+	}
+
+	return userIDs
+}
@@ -1,12 +1,16 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
-	"math/rand"
+	"log"
+	"slices"
 	"time"
 
 	"pr-reviewer-service/internal/models"
-	"pr-reviewer-service/internal/repository"
 )
 
 var (
@@ -16,30 +20,97 @@ var (
 	ErrNotAssigned = errors.New("NOT_ASSIGNED")
 	ErrNoCandidate = errors.New("NO_CANDIDATE")
 	ErrNotFound    = errors.New("NOT_FOUND")
+
+	ErrUserInAnotherTeam = errors.New("USER_IN_ANOTHER_TEAM")
+	ErrHasOpenPRs        = errors.New("HAS_OPEN_PRS")
+
+	ErrAlreadyAssigned  = errors.New("ALREADY_ASSIGNED")
+	ErrReviewerIsAuthor = errors.New("REVIEWER_IS_AUTHOR")
+	ErrReviewerInactive = errors.New("REVIEWER_INACTIVE")
+	ErrNotTeamMember    = errors.New("NOT_TEAM_MEMBER")
+
+	ErrInvalidReviewState = errors.New("INVALID_REVIEW_STATE")
+
+	ErrInvalidRequiredApprovals = errors.New("INVALID_REQUIRED_APPROVALS")
+	ErrMergeBlocked             = errors.New("MERGE_BLOCKED")
+	ErrNotEnoughReviewers       = errors.New("NOT_ENOUGH_REVIEWERS")
+
+	ErrInvalidTransition = errors.New("INVALID_TRANSITION")
+	ErrPRNotOpen         = errors.New("PR_NOT_OPEN")
+
+	ErrInvalidStrategy       = errors.New("INVALID_STRATEGY")
+	ErrInvalidReviewersCount = errors.New("INVALID_REVIEWERS_COUNT")
+	ErrInvalidFallbackTeam   = errors.New("INVALID_FALLBACK_TEAM")
+	ErrInvalidCursor         = errors.New("INVALID_CURSOR")
 )
 
 const (
-	reviewersCount = 2
+	DefaultReviewersCount = 2
+	MinReviewersCount     = 1
+	MaxReviewersCount     = 5
+
+	DefaultPageLimit = 50
+	MaxPageLimit     = 100
 )
 
 type Service struct {
-	userRepo *repository.UserRepository
-	teamRepo *repository.TeamRepository
-	prRepo   *repository.PRRepository
-	rand     *rand.Rand
+	userRepo    UserStorage
+	teamRepo    TeamStorage
+	prRepo      PRStorage
+	webhookRepo WebhookStorage
+	tx          TxManager
+	selectors   map[string]ReviewerSelector
 }
 
-func NewService(userRepo *repository.UserRepository, teamRepo *repository.TeamRepository, prRepo *repository.PRRepository) *Service {
+func NewService(
+	userRepo UserStorage,
+	teamRepo TeamStorage,
+	prRepo PRStorage,
+	webhookRepo WebhookStorage,
+	tx TxManager,
+) *Service {
 	return &Service{
-		userRepo: userRepo,
-		teamRepo: teamRepo,
-		prRepo:   prRepo,
-		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		userRepo:    userRepo,
+		teamRepo:    teamRepo,
+		prRepo:      prRepo,
+		webhookRepo: webhookRepo,
+		tx:          tx,
+		selectors: map[string]ReviewerSelector{
+			models.StrategyRandom:      NewRandomSelector(),
+			models.StrategyRoundRobin:  NewRoundRobinSelector(),
+			models.StrategyLeastLoaded: NewLeastLoadedSelector(prRepo),
+		},
+	}
+}
+
+func (s *Service) CreateTeam(ctx context.Context, team *models.Team) error {
+	if team.ReviewerStrategy == "" {
+		team.ReviewerStrategy = models.StrategyRandom
+	}
+	if _, ok := s.selectors[team.ReviewerStrategy]; !ok {
+		return ErrInvalidStrategy
+	}
+	if team.ReviewersCount == 0 {
+		team.ReviewersCount = DefaultReviewersCount
+	}
+	if !isValidReviewersCount(team.ReviewersCount) {
+		return ErrInvalidReviewersCount
+	}
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.createTeam(ctx, team)
+	})
+	if errors.Is(err, ErrDuplicateKey) {
+		return ErrTeamExists
 	}
+	return err
 }
 
-func (s *Service) CreateTeam(team *models.Team) error {
-	exists, err := s.teamRepo.Exists(team.TeamName)
+// createTeam inserts the team with its members; the caller runs it in a unit
+// of work so that a failing member leaves no half-created team behind. Members
+// of another team are rejected rather than moved out of it.
+func (s *Service) createTeam(ctx context.Context, team *models.Team) error {
+	exists, err := s.teamRepo.Exists(ctx, team.TeamName)
 	if err != nil {
 		return err
 	}
@@ -47,7 +118,13 @@ func (s *Service) CreateTeam(team *models.Team) error {
 		return ErrTeamExists
 	}
 
-	if err := s.teamRepo.Create(team.TeamName); err != nil {
+	for _, member := range team.Members {
+		if err = s.checkNotInAnotherTeam(ctx, member.UserID, team.TeamName); err != nil {
+			return err
+		}
+	}
+
+	if err = s.teamRepo.Create(ctx, team); err != nil {
 		return err
 	}
 
@@ -58,7 +135,7 @@ func (s *Service) CreateTeam(team *models.Team) error {
 			TeamName: team.TeamName,
 			IsActive: member.IsActive,
 		}
-		if err := s.userRepo.Create(user); err != nil {
+		if err := s.userRepo.Create(ctx, user); err != nil {
 			return err
 		}
 	}
@@ -66,8 +143,8 @@ func (s *Service) CreateTeam(team *models.Team) error {
 	return nil
 }
 
-func (s *Service) GetTeam(teamName string) (*models.Team, error) {
-	team, err := s.teamRepo.Get(teamName)
+func (s *Service) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	team, err := s.teamRepo.Get(ctx, teamName)
 	if err != nil {
 		return nil, err
 	}
@@ -77,21 +154,173 @@ func (s *Service) GetTeam(teamName string) (*models.Team, error) {
 	return team, nil
 }
 
-func (s *Service) SetUserActive(userID string, isActive bool) (*models.User, error) {
-	if err := s.userRepo.UpdateIsActive(userID, isActive); err != nil {
+func (s *Service) UpdateTeamSettings(ctx context.Context, teamName string, patch *models.TeamSettingsPatch) (*models.TeamSettings, error) {
+	settings, err := s.teamRepo.GetSettings(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
 		return nil, ErrNotFound
 	}
 
-	user, err := s.userRepo.GetByID(userID)
+	if patch.ReviewerStrategy != nil {
+		if _, ok := s.selectors[*patch.ReviewerStrategy]; !ok {
+			return nil, ErrInvalidStrategy
+		}
+		settings.ReviewerStrategy = *patch.ReviewerStrategy
+	}
+
+	if patch.ReviewersCount != nil {
+		if !isValidReviewersCount(*patch.ReviewersCount) {
+			return nil, ErrInvalidReviewersCount
+		}
+		settings.ReviewersCount = *patch.ReviewersCount
+	}
+
+	if patch.RequiredApprovals != nil {
+		if *patch.RequiredApprovals < 0 || *patch.RequiredApprovals > MaxReviewersCount {
+			return nil, ErrInvalidRequiredApprovals
+		}
+		settings.RequiredApprovals = *patch.RequiredApprovals
+	}
+
+	// a pull request gets no more reviewers than reviewers_count, so it could
+	// never collect more approvals
+	if settings.RequiredApprovals > settings.ReviewersCount {
+		return nil, ErrInvalidRequiredApprovals
+	}
+
+	if patch.FallbackTeams != nil {
+		if err := s.validateFallbackTeams(ctx, teamName, *patch.FallbackTeams); err != nil {
+			return nil, err
+		}
+		settings.FallbackTeams = *patch.FallbackTeams
+	}
+
+	if err := s.teamRepo.UpdateSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// SetUserActive flips the user's active flag. When a user is deactivated with
+// reassignReviews set, each of their reviews on OPEN pull requests is handed
+// over as ReassignReviewer would do it and the outcome is returned alongside
+// the user. Activating a user tops up under-staffed pull requests of their
+// team and of the teams falling back to it afterwards; a failed top-up does
+// not undo the activation and is only logged.
+func (s *Service) SetUserActive(ctx context.Context, userID string, isActive, reassignReviews bool) (*models.User, *models.DeactivationResult, error) {
+	var (
+		user   *models.User
+		result *models.DeactivationResult
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		user, result, err = s.setUserActive(ctx, userID, isActive, reassignReviews)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if isActive && user.TeamName != "" {
+		s.topUpAfterActivation(ctx, user.TeamName)
+	}
+
+	return user, result, nil
+}
+
+// topUpAfterActivation tops up the pull requests that a new active member of
+// teamName may review: the ones of the team itself and of the teams falling
+// back to it.
+func (s *Service) topUpAfterActivation(ctx context.Context, teamName string) {
+	dependents, err := s.teamRepo.GetFallingBackTo(ctx, teamName)
+	if err != nil {
+		log.Printf("listing teams falling back to %q failed: %v", teamName, err)
+	}
+
+	for _, name := range append([]string{teamName}, dependents...) {
+		if _, err := s.TopUpReviewers(ctx, name); err != nil {
+			log.Printf("reviewer top-up for team %q failed: %v", name, err)
+		}
+	}
+}
+
+func (s *Service) setUserActive(ctx context.Context, userID string, isActive, reassignReviews bool) (*models.User, *models.DeactivationResult, error) {
+	if !isActive && reassignReviews {
+		return s.deactivateAndReassign(ctx, userID)
+	}
+
+	if err := s.userRepo.UpdateIsActive(ctx, userID, isActive); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, nil, nil
+}
+
+func (s *Service) deactivateAndReassign(ctx context.Context, userID string) (*models.User, *models.DeactivationResult, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, ErrNotFound
+	}
+
+	if err = s.userRepo.UpdateIsActive(ctx, userID, false); err != nil {
+		return nil, nil, err
+	}
+
+	reassigned, unreassigned, err := s.reassignReviewsOf(ctx, []string{userID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user.IsActive = false
+	return user, &models.DeactivationResult{
+		DeactivatedUsers: []string{userID},
+		Reassigned:       reassigned,
+		Unreassigned:     unreassigned,
+	}, nil
+}
+
+// CreatePullRequest creates an OPEN pull request and assigns reviewers from
+// the author's team. A zero reviewersCount means the team default. A draft
+// pull request gets its reviewers once it is marked ready for review.
+func (s *Service) CreatePullRequest(ctx context.Context, prID, prName, authorID string, reviewersCount int, draft bool) (*models.PullRequest, error) {
+	if reviewersCount != 0 && !isValidReviewersCount(reviewersCount) {
+		return nil, ErrInvalidReviewersCount
+	}
+
+	var pr *models.PullRequest
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = s.createPullRequest(ctx, prID, prName, authorID, reviewersCount, draft)
+		return err
+	})
+	// a concurrent request with the same ID can win the race after the
+	// existence check
+	if errors.Is(err, ErrDuplicateKey) {
+		return nil, ErrPRExists
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return user, nil
+	return pr, nil
 }
 
-func (s *Service) CreatePullRequest(prID, prName, authorID string) (*models.PullRequest, error) {
-	exists, err := s.prRepo.Exists(prID)
+func (s *Service) createPullRequest(ctx context.Context, prID, prName, authorID string, reviewersCount int, draft bool) (*models.PullRequest, error) {
+	exists, err := s.prRepo.Exists(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +328,7 @@ func (s *Service) CreatePullRequest(prID, prName, authorID string) (*models.Pull
 		return nil, ErrPRExists
 	}
 
-	author, err := s.userRepo.GetByID(authorID)
+	author, err := s.userRepo.GetByID(ctx, authorID)
 	if err != nil {
 		return nil, err
 	}
@@ -107,30 +336,101 @@ func (s *Service) CreatePullRequest(prID, prName, authorID string) (*models.Pull
 		return nil, ErrNotFound
 	}
 
-	candidates, err := s.userRepo.GetActiveTeamMembers(author.TeamName, authorID)
+	settings, err := s.getTeamSettings(ctx, author.TeamName)
 	if err != nil {
 		return nil, err
 	}
+	if reviewersCount == 0 {
+		reviewersCount = settings.ReviewersCount
+	}
 
-	reviewers := s.selectRandomReviewers(candidates, reviewersCount)
+	now := time.Now()
+	if draft {
+		pr := &models.PullRequest{
+			CreatedAt:         &now,
+			PullRequestID:     prID,
+			PullRequestName:   prName,
+			AuthorID:          authorID,
+			Status:            models.StatusDraft,
+			AssignedReviewers: []string{},
+			RequiredReviewers: reviewersCount,
+		}
+		if createErr := s.prRepo.Create(ctx, pr, nil); createErr != nil {
+			return nil, createErr
+		}
+		history := []models.HistoryEntry{statusEntry(prID, models.HistoryCreated, models.StatusDraft, "", now)}
+		if historyErr := s.prRepo.AddHistory(ctx, history); historyErr != nil {
+			return nil, historyErr
+		}
+		return pr, nil
+	}
+
+	teamNames := append([]string{author.TeamName}, settings.FallbackTeams...)
+	reviewers, fallbackReviewers, err := s.pickReviewers(ctx, author.TeamName, teamNames, []string{authorID}, reviewersCount)
+	if err != nil {
+		return nil, err
+	}
 
 	pr := &models.PullRequest{
+		CreatedAt:         &now,
 		PullRequestID:     prID,
 		PullRequestName:   prName,
 		AuthorID:          authorID,
 		Status:            models.StatusOpen,
 		AssignedReviewers: reviewers,
+		RequiredReviewers: reviewersCount,
+		FallbackReviewers: fallbackReviewers,
 	}
 
-	if err := s.prRepo.Create(pr); err != nil {
+	event, err := newEvent(models.EventReviewersAssigned, author.TeamName, prID, now, models.ReviewersAssignedData{
+		PullRequest: pr,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.prRepo.Create(ctx, pr, event); err != nil {
+		return nil, err
+	}
+
+	history := append(
+		[]models.HistoryEntry{statusEntry(prID, models.HistoryCreated, models.StatusOpen, "", now)},
+		reviewerEntries(prID, models.HistoryReviewerAssigned, models.ReasonAuto, reviewers, now)...,
+	)
+	if err = s.prRepo.AddHistory(ctx, history); err != nil {
 		return nil, err
 	}
 
 	return pr, nil
 }
 
-func (s *Service) MergePullRequest(prID string) (*models.PullRequest, error) {
-	pr, err := s.prRepo.GetByID(prID)
+// MergePullRequest marks the pull request MERGED. Merging a merged pull
+// request returns it unchanged, also when two merges race. Unless force is
+// set, the pull request must pass the approval gate of the author's team.
+func (s *Service) MergePullRequest(ctx context.Context, prID string, force bool) (*models.PullRequest, error) {
+	var pr *models.PullRequest
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, err = s.mergePullRequest(ctx, prID, force)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+func (s *Service) mergePullRequest(ctx context.Context, prID string, force bool) (*models.PullRequest, error) {
+	exists, err := s.prRepo.Lock(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
@@ -141,12 +441,52 @@ func (s *Service) MergePullRequest(prID string) (*models.PullRequest, error) {
 	if pr.Status == models.StatusMerged {
 		return pr, nil
 	}
+	if transitionErr := checkTransition(pr.Status, models.StatusMerged); transitionErr != nil {
+		return nil, transitionErr
+	}
+
+	teamName, err := s.authorTeam(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !force {
+		settings, settingsErr := s.getTeamSettings(ctx, teamName)
+		if settingsErr != nil {
+			return nil, settingsErr
+		}
+		if gateErr := checkMergeGate(pr, settings); gateErr != nil {
+			return nil, gateErr
+		}
+	}
+
+	now := time.Now()
+	merged := *pr
+	merged.Status = models.StatusMerged
+	merged.MergedAt = &now
+
+	event, err := newEvent(models.EventPullRequestMerged, teamName, prID, now, models.PullRequestMergedData{
+		PullRequest: &merged,
+		Forced:      force,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	if updateErr := s.prRepo.UpdateStatus(prID, models.StatusMerged); updateErr != nil {
+	if updateErr := s.prRepo.UpdateStatus(ctx, prID, models.StatusMerged, now, event); updateErr != nil {
 		return nil, updateErr
 	}
 
-	pr, err = s.prRepo.GetByID(prID)
+	var reason string
+	if force {
+		reason = models.ReasonForced
+	}
+	history := []models.HistoryEntry{statusEntry(prID, models.HistoryStatusChanged, models.StatusMerged, reason, now)}
+	if historyErr := s.prRepo.AddHistory(ctx, history); historyErr != nil {
+		return nil, historyErr
+	}
+
+	pr, err = s.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
@@ -154,20 +494,31 @@ func (s *Service) MergePullRequest(prID string) (*models.PullRequest, error) {
 	return pr, nil
 }
 
-func (s *Service) ReassignReviewer(prID, oldReviewerID string) (*models.PullRequest, string, error) {
-	pr, err := s.prRepo.GetByID(prID)
+// ReassignReviewer replaces oldReviewerID with newReviewerID or, when that is
+// empty, with another member of their team, or else of the author's team and
+// its fallback teams. Concurrent reassignments of the same pull request run one
+// after another.
+func (s *Service) ReassignReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) (*models.PullRequest, string, error) {
+	var pr *models.PullRequest
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		pr, newReviewerID, err = s.reassignReviewer(ctx, prID, oldReviewerID, newReviewerID)
+		return err
+	})
 	if err != nil {
 		return nil, "", err
 	}
-	if pr == nil {
-		return nil, "", ErrNotFound
-	}
 
-	if pr.Status == models.StatusMerged {
-		return nil, "", ErrPRMerged
+	return pr, newReviewerID, nil
+}
+
+func (s *Service) reassignReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) (*models.PullRequest, string, error) {
+	pr, err := s.lockOpenPullRequest(ctx, prID)
+	if err != nil {
+		return nil, "", err
 	}
 
-	isAssigned, err := s.prRepo.IsReviewerAssigned(prID, oldReviewerID)
+	isAssigned, err := s.prRepo.IsReviewerAssigned(ctx, prID, oldReviewerID)
 	if err != nil {
 		return nil, "", err
 	}
@@ -175,7 +526,7 @@ func (s *Service) ReassignReviewer(prID, oldReviewerID string) (*models.PullRequ
 		return nil, "", ErrNotAssigned
 	}
 
-	oldReviewer, err := s.userRepo.GetByID(oldReviewerID)
+	oldReviewer, err := s.userRepo.GetByID(ctx, oldReviewerID)
 	if err != nil {
 		return nil, "", err
 	}
@@ -183,45 +534,192 @@ func (s *Service) ReassignReviewer(prID, oldReviewerID string) (*models.PullRequ
 		return nil, "", ErrNotFound
 	}
 
-	candidates, err := s.userRepo.GetActiveTeamMembers(oldReviewer.TeamName, oldReviewerID)
+	reason := models.ReasonManual
+	if newReviewerID == "" {
+		reason = models.ReasonAuto
+	}
+
+	newReviewerID, err = s.replaceReviewer(ctx, pr, oldReviewer, newReviewerID, reason)
 	if err != nil {
 		return nil, "", err
 	}
 
-	var availableCandidates []models.User
-	for _, candidate := range candidates {
-		isCurrentReviewer := false
-		for _, reviewerID := range pr.AssignedReviewers {
-			if candidate.UserID == reviewerID {
-				isCurrentReviewer = true
-				break
-			}
+	pr, err = s.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return pr, newReviewerID, nil
+}
+
+// replaceReviewer swaps oldReviewer for newReviewerID on the locked pull request
+// pr and records the swap with the given history reason. An empty newReviewerID
+// picks a member of the old reviewer's team, or else of the author's team and
+// its fallback teams. It returns the reviewer that took over.
+func (s *Service) replaceReviewer(ctx context.Context, pr *models.PullRequest, oldReviewer *models.User, newReviewerID, reason string) (string, error) {
+	oldReviewerID := oldReviewer.UserID
+
+	teamName, err := s.authorTeam(ctx, pr.AuthorID)
+	if err != nil {
+		return "", err
+	}
+
+	settings, err := s.getTeamSettings(ctx, teamName)
+	if err != nil {
+		return "", err
+	}
+
+	var fromFallback bool
+	if newReviewerID != "" {
+		fromFallback, err = s.checkReviewer(ctx, pr, settings, newReviewerID)
+		if err != nil {
+			return "", err
 		}
-		if !isCurrentReviewer {
-			availableCandidates = append(availableCandidates, candidate)
+	} else {
+		// the author is never a candidate, otherwise load-aware strategies would
+		// favor them as the member with the fewest reviews
+		teamNames := append([]string{oldReviewer.TeamName, teamName}, settings.FallbackTeams...)
+		excludeIDs := append(slices.Clone(pr.AssignedReviewers), pr.AuthorID)
+		selected, fallbackReviewers, pickErr := s.pickReviewers(ctx, teamName, teamNames, excludeIDs, 1)
+		if pickErr != nil {
+			return "", pickErr
 		}
+		if len(selected) == 0 {
+			return "", ErrNoCandidate
+		}
+		newReviewerID = selected[0]
+		fromFallback = len(fallbackReviewers) > 0
 	}
 
-	if len(availableCandidates) == 0 {
-		return nil, "", ErrNoCandidate
+	reassigned := *pr
+	reassigned.Reviewers = nil
+	reassigned.AssignedReviewers = make([]string, 0, len(pr.AssignedReviewers))
+	for _, reviewerID := range pr.AssignedReviewers {
+		if reviewerID == oldReviewerID {
+			reviewerID = newReviewerID
+		}
+		reassigned.AssignedReviewers = append(reassigned.AssignedReviewers, reviewerID)
+	}
+	reassigned.FallbackReviewers = slices.DeleteFunc(slices.Clone(pr.FallbackReviewers), func(reviewerID string) bool {
+		return reviewerID == oldReviewerID
+	})
+	if fromFallback {
+		reassigned.FallbackReviewers = append(reassigned.FallbackReviewers, newReviewerID)
 	}
 
-	newReviewer := availableCandidates[s.rand.Intn(len(availableCandidates))]
+	now := time.Now()
+	event, err := newEvent(models.EventReviewerReassigned, teamName, pr.PullRequestID, now, models.ReviewerReassignedData{
+		PullRequest: &reassigned,
+		OldUserID:   oldReviewerID,
+		NewUserID:   newReviewerID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if replaceErr := s.prRepo.ReplaceReviewer(ctx, pr.PullRequestID, oldReviewerID, newReviewerID, fromFallback, event); replaceErr != nil {
+		return "", replaceErr
+	}
 
-	if replaceErr := s.prRepo.ReplaceReviewer(prID, oldReviewerID, newReviewer.UserID); replaceErr != nil {
-		return nil, "", replaceErr
+	history := []models.HistoryEntry{reassignEntry(pr.PullRequestID, oldReviewerID, newReviewerID, reason, now)}
+	if historyErr := s.prRepo.AddHistory(ctx, history); historyErr != nil {
+		return "", historyErr
 	}
 
-	pr, err = s.prRepo.GetByID(prID)
+	return newReviewerID, nil
+}
+
+func (s *Service) GetPullRequest(ctx context.Context, prID string) (*models.PullRequest, error) {
+	pr, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
-		return nil, "", err
+		return nil, err
+	}
+	if pr == nil {
+		return nil, ErrNotFound
+	}
+	if pr.AssignedReviewers == nil {
+		pr.AssignedReviewers = []string{}
+	}
+
+	return pr, nil
+}
+
+// ListPullRequests returns a page of pull requests matching the filter and
+// an opaque cursor for the next page, if there is one.
+func (s *Service) ListPullRequests(ctx context.Context, filter *models.PullRequestFilter, cursor string) (*models.PullRequestPage, error) {
+	if filter.SortBy == "" {
+		filter.SortBy = models.SortByCreatedAt
+	}
+	if filter.Order == "" {
+		filter.Order = models.OrderDesc
+	}
+	filter.Limit = pageLimit(filter.Limit)
+
+	if cursor != "" {
+		var after models.PullRequestCursor
+		if err := decodeCursor(cursor, &after); err != nil || after.PullRequestID == "" {
+			return nil, ErrInvalidCursor
+		}
+		// a cursor of another order would silently skip or repeat rows
+		if after.SortBy != filter.SortBy || after.Order != filter.Order {
+			return nil, ErrInvalidCursor
+		}
+		filter.After = &after
+	}
+
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	prs, err := s.prRepo.List(ctx, filter)
+	filter.Limit = limit
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.PullRequestPage{PullRequests: prs}
+	if len(prs) > limit {
+		page.PullRequests = prs[:limit]
+		last := page.PullRequests[limit-1]
+		page.NextCursor = encodeCursor(&models.PullRequestCursor{
+			CreatedAt:     *last.CreatedAt,
+			PullRequestID: last.PullRequestID,
+			SortBy:        filter.SortBy,
+			Order:         filter.Order,
+		})
+	}
+	if page.PullRequests == nil {
+		page.PullRequests = []models.PullRequest{}
+	}
+
+	return page, nil
+}
+
+// GetReviewerStats returns review load per user, for a single team when
+// teamName is set.
+func (s *Service) GetReviewerStats(ctx context.Context, teamName string) ([]models.ReviewerStats, error) {
+	if teamName != "" {
+		exists, err := s.teamRepo.Exists(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrNotFound
+		}
+	}
+
+	stats, err := s.prRepo.GetReviewerStats(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	if stats == nil {
+		stats = []models.ReviewerStats{}
 	}
 
-	return pr, newReviewer.UserID, nil
+	return stats, nil
 }
 
-func (s *Service) GetUserReviews(userID string) ([]models.PullRequestShort, error) {
-	prs, err := s.prRepo.GetByReviewer(userID)
+func (s *Service) GetUserReviews(ctx context.Context, userID string) ([]models.PullRequestShort, error) {
+	prs, err := s.prRepo.GetByReviewer(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -233,25 +731,157 @@ func (s *Service) GetUserReviews(userID string) ([]models.PullRequestShort, erro
 	return prs, nil
 }
 
-func (s *Service) selectRandomReviewers(candidates []models.User, n int) []string {
+// getTeamSettings returns the team settings, falling back to defaults for
+// teams created before the settings existed.
+func (s *Service) getTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	settings, err := s.teamRepo.GetSettings(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = &models.TeamSettings{TeamName: teamName}
+	}
+
+	if _, ok := s.selectors[settings.ReviewerStrategy]; !ok {
+		settings.ReviewerStrategy = models.StrategyRandom
+	}
+	if !isValidReviewersCount(settings.ReviewersCount) {
+		settings.ReviewersCount = DefaultReviewersCount
+	}
+
+	return settings, nil
+}
+
+// selectReviewers picks up to n reviewers out of candidates using the
+// strategy configured for the team.
+func (s *Service) selectReviewers(ctx context.Context, settings *models.TeamSettings, candidates []models.User, n int) ([]string, error) {
 	if len(candidates) == 0 {
-		return []string{}
+		return []string{}, nil
 	}
 
-	if n > len(candidates) {
-		n = len(candidates)
+	return s.selectors[settings.ReviewerStrategy].Select(ctx, settings.TeamName, candidates, n)
+}
+
+// pickReviewers picks up to n reviewers from the first of teamNames and moves on
+// to the next team only while fewer than n are picked. Users in excludeIDs are
+// never picked. Reviewers picked outside homeTeam are also returned as fallback.
+func (s *Service) pickReviewers(ctx context.Context, homeTeam string, teamNames, excludeIDs []string, n int) (reviewers, fallback []string, err error) {
+	reviewers = []string{}
+
+	asked := make(map[string]struct{}, len(teamNames))
+	for _, teamName := range teamNames {
+		if len(reviewers) >= n {
+			break
+		}
+		if _, ok := asked[teamName]; ok || teamName == "" {
+			continue
+		}
+		asked[teamName] = struct{}{}
+
+		members, err := s.userRepo.GetActiveTeamMembers(ctx, teamName, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		candidates := excludeUsers(members, slices.Concat(excludeIDs, reviewers))
+
+		settings, err := s.getTeamSettings(ctx, teamName)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		selected, err := s.selectReviewers(ctx, settings, candidates, n-len(reviewers))
+		if err != nil {
+			return nil, nil, err
+		}
+		reviewers = append(reviewers, selected...)
+		if teamName != homeTeam {
+			fallback = append(fallback, selected...)
+		}
 	}
 
-	shuffled := make([]models.User, len(candidates))
-	copy(shuffled, candidates)
-	s.rand.Shuffle(len(shuffled), func(i, j int) {
-		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
-	})
+	return reviewers, fallback, nil
+}
 
-	reviewers := make([]string, n)
-	for i := 0; i < n; i++ {
-		reviewers[i] = shuffled[i].UserID
+// validateFallbackTeams checks that every fallback team is another existing
+// team, listed once.
+func (s *Service) validateFallbackTeams(ctx context.Context, teamName string, fallbackTeams []string) error {
+	for i, fallbackTeam := range fallbackTeams {
+		if fallbackTeam == teamName || slices.Contains(fallbackTeams[:i], fallbackTeam) {
+			return ErrInvalidFallbackTeam
+		}
+
+		exists, err := s.teamRepo.Exists(ctx, fallbackTeam)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrInvalidFallbackTeam
+		}
 	}
 
-	return reviewers
+	return nil
+}
+
+func isValidReviewersCount(n int) bool {
+	return n >= MinReviewersCount && n <= MaxReviewersCount
+}
+
+func excludeUsers(users []models.User, excludeIDs []string) []models.User {
+	excluded := make(map[string]struct{}, len(excludeIDs))
+	for _, userID := range excludeIDs {
+		excluded[userID] = struct{}{}
+	}
+
+	var result []models.User
+	for _, user := range users {
+		if _, ok := excluded[user.UserID]; !ok {
+			result = append(result, user)
+		}
+	}
+
+	return result
+}
+
+// pageLimit applies the default and the maximum to a requested page size.
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return limit
+}
+
+// keyCursor resumes listings ordered by a single unique key.
+type keyCursor struct {
+	Key string `json:"key"`
+}
+
+func decodeKeyCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+
+	var after keyCursor
+	if err := decodeCursor(cursor, &after); err != nil || after.Key == "" {
+		return "", ErrInvalidCursor
+	}
+	return after.Key, nil
+}
+
+func encodeCursor(cursor any) string {
+	data, _ := json.Marshal(cursor) // nolint:errcheck // marshaling a plain struct cannot fail
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor string, into any) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
 }
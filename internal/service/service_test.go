@@ -1,9 +1,12 @@
 package service_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/service"
 )
 
 func TestSelectRandomReviewers(t *testing.T) {
@@ -17,7 +20,138 @@ func TestSelectRandomReviewers(t *testing.T) {
 		if len(candidates) < 2 {
 			t.Error("expected at least 2 candidates")
 		}
+
+		reviewers, err := service.NewRandomSelector().Select(context.Background(), "team", candidates, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(reviewers) != 2 {
+			t.Fatalf("expected 2 reviewers, got %v", reviewers)
+		}
+		if reviewers[0] == reviewers[1] {
+			t.Errorf("expected distinct reviewers, got %v", reviewers)
+		}
+	})
+
+	t.Run("select more than available", func(t *testing.T) {
+		reviewers, err := service.NewRandomSelector().Select(context.Background(), "team", candidates[:1], 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(reviewers) != 1 || reviewers[0] != "u1" {
+			t.Errorf("expected [u1], got %v", reviewers)
+		}
+	})
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	candidates := []models.User{
+		{UserID: "u3", Username: "charlie", IsActive: true},
+		{UserID: "u1", Username: "alice", IsActive: true},
+		{UserID: "u2", Username: "bob", IsActive: true},
+	}
+
+	selector := service.NewRoundRobinSelector()
+
+	expected := [][]string{{"u1", "u2"}, {"u3", "u1"}, {"u2", "u3"}}
+	for i, want := range expected {
+		got, err := selector.Select(context.Background(), "team", candidates, 2)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("round %d: expected %v, got %v", i, want, got)
+		}
+	}
+
+	got, err := selector.Select(context.Background(), "other team", candidates, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != "u1" {
+		t.Errorf("expected other team to start from u1, got %v", got)
+	}
+}
+
+type fakeCounter map[string]int
+
+func (f fakeCounter) CountOpenReviews(_ context.Context, userIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(userIDs))
+	for _, userID := range userIDs {
+		counts[userID] = f[userID]
+	}
+	return counts, nil
+}
+
+func TestLeastLoadedSelector(t *testing.T) {
+	candidates := []models.User{
+		{UserID: "u1", Username: "alice", IsActive: true},
+		{UserID: "u2", Username: "bob", IsActive: true},
+		{UserID: "u3", Username: "charlie", IsActive: true},
+	}
+
+	selector := service.NewLeastLoadedSelector(fakeCounter{"u1": 5, "u2": 0, "u3": 1})
+
+	got, err := selector.Select(context.Background(), "team", candidates, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != "u2" || got[1] != "u3" {
+		t.Errorf("expected [u2 u3], got %v", got)
+	}
+
+	t.Run("ties broken by user_id", func(t *testing.T) {
+		selector := service.NewLeastLoadedSelector(fakeCounter{"u1": 2, "u2": 1, "u3": 1})
+
+		for i := 0; i < 10; i++ {
+			got, err := selector.Select(context.Background(), "team", candidates, 2)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != 2 || got[0] != "u2" || got[1] != "u3" {
+				t.Fatalf("expected [u2 u3], got %v", got)
+			}
+		}
 	})
+
+	t.Run("counter error", func(t *testing.T) {
+		selector := service.NewLeastLoadedSelector(failingCounter{})
+
+		if _, err := selector.Select(context.Background(), "team", candidates, 2); err == nil {
+			t.Error("expected counter error to be returned")
+		}
+	})
+}
+
+type failingCounter struct{}
+
+func (failingCounter) CountOpenReviews(context.Context, []string) (map[string]int, error) {
+	return nil, errors.New("db is down")
+}
+
+// failingUsers fails every activation change; other methods are not expected
+// to be called.
+type failingUsers struct {
+	service.UserStorage
+}
+
+func (failingUsers) UpdateIsActive(context.Context, string, bool) error {
+	return errors.New("db is down")
+}
+
+type directTx struct{}
+
+func (directTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestSetUserActiveStorageError(t *testing.T) {
+	svc := service.NewService(failingUsers{}, nil, nil, nil, directTx{})
+
+	_, _, err := svc.SetUserActive(context.Background(), "u1", true, false)
+	if err == nil || errors.Is(err, service.ErrNotFound) {
+		t.Errorf("expected the storage error, got %v", err)
+	}
 }
 
 func TestPRStatusTransitions(t *testing.T) {
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// The storage interfaces describe what Service needs from a backend. Getters
+// return nil without an error when nothing is found; updates and deletes of a
+// missing row return sql.ErrNoRows, and writes that violate a unique key
+// return an error wrapping ErrDuplicateKey.
+
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// TxManager runs fn as one unit of work: storage calls made with the context
+// passed to fn either all take effect or none do.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// UserStorage keeps users that left their team with an empty team name.
+type UserStorage interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, userID string) (*models.User, error)
+	GetByTeam(ctx context.Context, teamName string) ([]models.User, error)
+	List(ctx context.Context, filter *models.UserFilter) ([]models.User, error)
+	UpdateIsActive(ctx context.Context, userID string, isActive bool) error
+	UpdateTeam(ctx context.Context, userID, teamName string) error
+	GetByIDs(ctx context.Context, userIDs []string) ([]models.User, error)
+	GetActiveTeamMembers(ctx context.Context, teamName, excludeUserID string) ([]models.User, error)
+	GetActiveMembersOfTeams(ctx context.Context, teamNames []string) ([]models.User, error)
+}
+
+type TeamStorage interface {
+	Create(ctx context.Context, team *models.Team) error
+	Exists(ctx context.Context, teamName string) (bool, error)
+	Get(ctx context.Context, teamName string) (*models.Team, error)
+	List(ctx context.Context, filter *models.TeamFilter) ([]models.TeamSummary, error)
+	GetSettings(ctx context.Context, teamName string) (*models.TeamSettings, error)
+	GetFallingBackTo(ctx context.Context, teamName string) ([]string, error)
+	UpdateSettings(ctx context.Context, settings *models.TeamSettings) error
+	Rename(ctx context.Context, teamName, newName string) error
+	Delete(ctx context.Context, teamName string) error
+}
+
+// PRStorage writes the given event, when not nil, atomically with the change.
+// Lock locks the pull request until the end of the unit of work, so that
+// concurrent changes to it run one after another; it reports whether the pull
+// request exists; LockAll locks several pull requests the same way.
+type PRStorage interface {
+	OpenReviewCounter
+
+	Create(ctx context.Context, pr *models.PullRequest, event *models.Event) error
+	GetByID(ctx context.Context, prID string) (*models.PullRequest, error)
+	GetByIDs(ctx context.Context, prIDs []string) ([]models.PullRequest, error)
+	Exists(ctx context.Context, prID string) (bool, error)
+	Lock(ctx context.Context, prID string) (bool, error)
+	LockAll(ctx context.Context, prIDs []string) error
+	UpdateStatus(ctx context.Context, prID, status string, changedAt time.Time, event *models.Event) error
+	ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string, fromFallback bool, event *models.Event) error
+	ReplaceReviewers(ctx context.Context, replacements []models.ReviewerReplacement, events []*models.Event) error
+	AddReviewers(ctx context.Context, prID string, reviewerIDs, fallbackIDs []string, event *models.Event) error
+	RemoveReviewer(ctx context.Context, prID, userID string, event *models.Event) error
+	SubmitReview(ctx context.Context, prID, userID, state string, reviewedAt time.Time, event *models.Event) error
+	UpdateRequiredReviewers(ctx context.Context, prID string, required int) error
+	IsReviewerAssigned(ctx context.Context, prID, userID string) (bool, error)
+	GetByReviewer(ctx context.Context, userID string) ([]models.PullRequestShort, error)
+	List(ctx context.Context, filter *models.PullRequestFilter) ([]models.PullRequest, error)
+	GetReviewerStats(ctx context.Context, teamName string) ([]models.ReviewerStats, error)
+	GetOpenReviewsOf(ctx context.Context, userIDs []string) ([]models.OpenReview, error)
+	HasOpenPullRequests(ctx context.Context, userIDs []string) (bool, error)
+	GetUnderstaffed(ctx context.Context, teamName string) ([]string, error)
+	AddHistory(ctx context.Context, entries []models.HistoryEntry) error
+	GetHistory(ctx context.Context, prID string) ([]models.HistoryEntry, error)
+}
+
+type WebhookStorage interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	GetByTeam(ctx context.Context, teamName string) ([]models.Webhook, error)
+	Delete(ctx context.Context, id int64) error
+}
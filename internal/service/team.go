@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// AddTeamMembers adds users to an existing team, creating the ones that do not
+// exist yet. Members of another team have to be transferred instead.
+func (s *Service) AddTeamMembers(ctx context.Context, teamName string, members []models.TeamMember) (*models.Team, error) {
+	var team *models.Team
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		exists, err := s.teamRepo.Exists(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrNotFound
+		}
+
+		for _, member := range members {
+			if err = s.checkNotInAnotherTeam(ctx, member.UserID, teamName); err != nil {
+				return err
+			}
+
+			user := &models.User{
+				UserID:   member.UserID,
+				Username: member.Username,
+				TeamName: teamName,
+				IsActive: member.IsActive,
+			}
+			if err = s.userRepo.Create(ctx, user); err != nil {
+				return err
+			}
+		}
+
+		team, err = s.teamRepo.Get(ctx, teamName)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// RemoveTeamMembers takes users out of the team. They keep their history but
+// are no longer picked as reviewers. Users with OPEN pull requests, authored
+// or under review, cannot leave until those are merged or reassigned.
+func (s *Service) RemoveTeamMembers(ctx context.Context, teamName string, userIDs []string) (*models.Team, error) {
+	var team *models.Team
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.teamRepo.Get(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return ErrNotFound
+		}
+
+		isMember := make(map[string]struct{}, len(current.Members))
+		for _, member := range current.Members {
+			isMember[member.UserID] = struct{}{}
+		}
+		for _, userID := range userIDs {
+			if _, ok := isMember[userID]; !ok {
+				return ErrNotFound
+			}
+		}
+
+		if err = s.checkNoOpenPullRequests(ctx, userIDs); err != nil {
+			return err
+		}
+
+		for _, userID := range userIDs {
+			if err = s.userRepo.UpdateTeam(ctx, userID, ""); err != nil {
+				return err
+			}
+		}
+
+		team, err = s.teamRepo.Get(ctx, teamName)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// TransferUser moves a user, or a user without a team, into another team.
+// Users with OPEN pull requests stay where they are, so that reassignments
+// keep picking candidates from the team the pull request was opened in.
+func (s *Service) TransferUser(ctx context.Context, userID, teamName string) (*models.User, error) {
+	var user *models.User
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrNotFound
+		}
+
+		exists, err := s.teamRepo.Exists(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrNotFound
+		}
+
+		if user.TeamName == teamName {
+			return nil
+		}
+
+		if err := s.checkNoOpenPullRequests(ctx, []string{userID}); err != nil {
+			return err
+		}
+
+		if err := s.userRepo.UpdateTeam(ctx, userID, teamName); err != nil {
+			return err
+		}
+		user.TeamName = teamName
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// RenameTeam changes the team name, keeping its settings, members and webhooks.
+func (s *Service) RenameTeam(ctx context.Context, teamName, newName string) (*models.Team, error) {
+	var team *models.Team
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if newName != teamName {
+			if err := s.teamRepo.Rename(ctx, teamName, newName); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		team, err = s.teamRepo.Get(ctx, newName)
+		if err != nil {
+			return err
+		}
+		if team == nil {
+			return ErrNotFound
+		}
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if errors.Is(err, ErrDuplicateKey) {
+		return nil, ErrTeamExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// DeleteTeam removes the team and its webhooks. The members stay as users
+// without a team; a team with OPEN pull requests of its members cannot be
+// deleted.
+func (s *Service) DeleteTeam(ctx context.Context, teamName string) error {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		members, err := s.userRepo.GetByTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		userIDs := make([]string, 0, len(members))
+		for _, member := range members {
+			userIDs = append(userIDs, member.UserID)
+		}
+		if err := s.checkNoOpenPullRequests(ctx, userIDs); err != nil {
+			return err
+		}
+
+		return s.teamRepo.Delete(ctx, teamName)
+	})
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	return err
+}
+
+// checkNotInAnotherTeam rejects a user who is a member of a team other than
+// teamName: moving members between teams goes through TransferUser.
+func (s *Service) checkNotInAnotherTeam(ctx context.Context, userID, teamName string) error {
+	existing, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.TeamName != "" && existing.TeamName != teamName {
+		return ErrUserInAnotherTeam
+	}
+	return nil
+}
+
+func (s *Service) checkNoOpenPullRequests(ctx context.Context, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	hasOpen, err := s.prRepo.HasOpenPullRequests(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	if hasOpen {
+		return ErrHasOpenPRs
+	}
+	return nil
+}
+
+// ListTeams returns a page of teams ordered by name, with their member counts,
+// and an opaque cursor for the next page, if there is one.
+func (s *Service) ListTeams(ctx context.Context, limit int, cursor string) (*models.TeamPage, error) {
+	after, err := decodeKeyCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit = pageLimit(limit)
+	teams, err := s.teamRepo.List(ctx, &models.TeamFilter{After: after, Limit: limit + 1})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.TeamPage{Teams: teams}
+	if len(teams) > limit {
+		page.Teams = teams[:limit]
+		page.NextCursor = encodeCursor(&keyCursor{Key: page.Teams[limit-1].TeamName})
+	}
+	if page.Teams == nil {
+		page.Teams = []models.TeamSummary{}
+	}
+
+	return page, nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// TopUpReviewers assigns more reviewers to OPEN pull requests that have fewer
+// than required, e.g. because the team was short of active members when they
+// were created. A non-empty teamName limits it to pull requests of the team's
+// authors. Every pull request is topped up in its own unit of work.
+func (s *Service) TopUpReviewers(ctx context.Context, teamName string) (*models.TopUpResult, error) {
+	if teamName != "" {
+		exists, err := s.teamRepo.Exists(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrNotFound
+		}
+	}
+
+	prIDs, err := s.prRepo.GetUnderstaffed(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.TopUpResult{
+		Assigned:     []models.ReviewerAssignment{},
+		Understaffed: []string{},
+	}
+	for _, prID := range prIDs {
+		var (
+			added        []string
+			understaffed bool
+		)
+		err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			added, understaffed, err = s.topUpPullRequest(ctx, prID)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, userID := range added {
+			result.Assigned = append(result.Assigned, models.ReviewerAssignment{PullRequestID: prID, UserID: userID})
+		}
+		if understaffed {
+			result.Understaffed = append(result.Understaffed, prID)
+		}
+	}
+
+	return result, nil
+}
+
+// topUpPullRequest adds the missing reviewers of one pull request from the
+// author's team and its fallback teams. It reports whether the pull request
+// still lacks reviewers afterwards.
+func (s *Service) topUpPullRequest(ctx context.Context, prID string) ([]string, bool, error) {
+	exists, err := s.prRepo.Lock(ctx, prID)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if err != nil || pr == nil {
+		return nil, false, err
+	}
+
+	// the pull request may have changed since it was listed
+	missing := pr.RequiredReviewers - len(pr.AssignedReviewers)
+	if pr.Status != models.StatusOpen || missing <= 0 {
+		return nil, false, nil
+	}
+
+	teamName, err := s.authorTeam(ctx, pr.AuthorID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	settings, err := s.getTeamSettings(ctx, teamName)
+	if err != nil {
+		return nil, false, err
+	}
+
+	teamNames := append([]string{teamName}, settings.FallbackTeams...)
+	excludeIDs := append(slices.Clone(pr.AssignedReviewers), pr.AuthorID)
+	added, fallbackReviewers, err := s.pickReviewers(ctx, teamName, teamNames, excludeIDs, missing)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(added) == 0 {
+		return nil, true, nil
+	}
+
+	toppedUp := *pr
+	toppedUp.Reviewers = nil
+	toppedUp.AssignedReviewers = slices.Concat(pr.AssignedReviewers, added)
+	toppedUp.FallbackReviewers = slices.Concat(pr.FallbackReviewers, fallbackReviewers)
+
+	now := time.Now()
+	event, err := newEvent(models.EventReviewersAdded, teamName, prID, now, models.ReviewersAddedData{
+		PullRequest: &toppedUp,
+		UserIDs:     added,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := s.prRepo.AddReviewers(ctx, prID, added, fallbackReviewers, event); err != nil {
+		return nil, false, err
+	}
+
+	history := reviewerEntries(prID, models.HistoryReviewerAssigned, models.ReasonTopUp, added, now)
+	if err := s.prRepo.AddHistory(ctx, history); err != nil {
+		return nil, false, err
+	}
+
+	return added, len(added) < missing, nil
+}
+
+// RunTopUp tops up under-staffed pull requests every interval until ctx is
+// canceled.
+func (s *Service) RunTopUp(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.TopUpReviewers(ctx, ""); err != nil && ctx.Err() == nil {
+			log.Printf("reviewer top-up failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+
+	"pr-reviewer-service/internal/models"
+)
+
+func (s *Service) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrNotFound
+	}
+
+	return user, nil
+}
+
+// ListUsers returns a page of users matching the filter, ordered by user_id,
+// and an opaque cursor for the next page, if there is one. Filtering by a team
+// that does not exist is an error rather than an empty page.
+func (s *Service) ListUsers(ctx context.Context, filter *models.UserFilter, cursor string) (*models.UserPage, error) {
+	after, err := decodeKeyCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	filter.After = after
+
+	if filter.TeamName != "" {
+		exists, existsErr := s.teamRepo.Exists(ctx, filter.TeamName)
+		if existsErr != nil {
+			return nil, existsErr
+		}
+		if !exists {
+			return nil, ErrNotFound
+		}
+	}
+
+	limit := pageLimit(filter.Limit)
+	filter.Limit = limit + 1
+	users, err := s.userRepo.List(ctx, filter)
+	filter.Limit = limit
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.UserPage{Users: users}
+	if len(users) > limit {
+		page.Users = users[:limit]
+		page.NextCursor = encodeCursor(&keyCursor{Key: page.Users[limit-1].UserID})
+	}
+	if page.Users == nil {
+		page.Users = []models.User{}
+	}
+
+	return page, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+
+	"pr-reviewer-service/internal/models"
+)
+
+func (s *Service) RegisterWebhook(ctx context.Context, webhook *models.Webhook) error {
+	exists, err := s.teamRepo.Exists(ctx, webhook.TeamName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+
+	if webhook.Secret == "" {
+		webhook.Secret, err = randomHex(32)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.webhookRepo.Create(ctx, webhook)
+}
+
+func (s *Service) ListWebhooks(ctx context.Context, teamName string) ([]models.Webhook, error) {
+	exists, err := s.teamRepo.Exists(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	webhooks, err := s.webhookRepo.GetByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhooks == nil {
+		webhooks = []models.Webhook{}
+	}
+
+	return webhooks, nil
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, id int64) error {
+	if err := s.webhookRepo.Delete(ctx, id); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,170 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+)
+
+const (
+	SignatureHeader  = "X-Signature-256"
+	EventTypeHeader  = "X-Event-Type"
+	DeliveryIDHeader = "X-Delivery-ID"
+)
+
+// Store is the persistence the dispatcher needs, implemented by repository.WebhookRepository.
+type Store interface {
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64, attempts int) error
+	MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id int64, attempts int, lastError string) error
+}
+
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Timeout      time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: time.Second,
+		BatchSize:    50,
+		MaxAttempts:  8,
+		BaseBackoff:  5 * time.Second,
+		MaxBackoff:   time.Hour,
+		Timeout:      10 * time.Second,
+	}
+}
+
+// Dispatcher delivers pending webhook deliveries, retrying failures with
+// exponential backoff until MaxAttempts is reached.
+type Dispatcher struct {
+	store  Store
+	client *http.Client
+	cfg    Config
+	now    func() time.Time
+}
+
+func NewDispatcher(store Store, cfg Config) *Dispatcher {
+	return &Dispatcher{
+		store:  store,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+		now:    time.Now,
+	}
+}
+
+// Run polls for due deliveries until ctx is canceled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DispatchDue(ctx); err != nil {
+			log.Printf("webhook dispatch failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue sends one batch of due deliveries.
+func (d *Dispatcher) DispatchDue(ctx context.Context) error {
+	// a claim outlives one request timeout so that a slow attempt is not picked up twice
+	deliveries, err := d.store.ClaimDue(ctx, d.now(), 2*d.cfg.Timeout, d.cfg.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := d.deliver(ctx, &deliveries[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *models.WebhookDelivery) error {
+	attempts := delivery.Attempts + 1
+
+	sendErr := d.send(ctx, delivery)
+
+	// record the attempt even if ctx was cancelled while sending
+	bookkeeping := context.WithoutCancel(ctx)
+	if sendErr == nil {
+		return d.store.MarkDelivered(bookkeeping, delivery.ID, attempts)
+	}
+
+	if attempts >= d.cfg.MaxAttempts {
+		return d.store.MarkFailed(bookkeeping, delivery.ID, attempts, sendErr.Error())
+	}
+
+	return d.store.MarkRetry(bookkeeping, delivery.ID, attempts, d.now().Add(d.Backoff(attempts)), sendErr.Error())
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *models.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventTypeHeader, delivery.EventType)
+	req.Header.Set(DeliveryIDHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body) // nolint:errcheck // drain to reuse the connection
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Backoff returns the delay before the attempt following the given failed one:
+// BaseBackoff doubled per failed attempt, capped at MaxBackoff.
+func (d *Dispatcher) Backoff(failedAttempts int) time.Duration {
+	delay := d.cfg.BaseBackoff
+	for i := 1; i < failedAttempts; i++ {
+		delay *= 2
+		if delay >= d.cfg.MaxBackoff {
+			return d.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// Sign returns the value of SignatureHeader for the payload: the hex encoded
+// HMAC-SHA256 of the body keyed with the webhook secret.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,142 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"pr-reviewer-service/internal/models"
+	"pr-reviewer-service/internal/webhook"
+)
+
+type fakeStore struct {
+	mu        sync.Mutex
+	due       []models.WebhookDelivery
+	delivered map[int64]int
+	retries   map[int64]time.Time
+	failed    map[int64]string
+}
+
+func newFakeStore(deliveries ...models.WebhookDelivery) *fakeStore {
+	return &fakeStore{
+		due:       deliveries,
+		delivered: map[int64]int{},
+		retries:   map[int64]time.Time{},
+		failed:    map[int64]string{},
+	}
+}
+
+func (f *fakeStore) ClaimDue(_ context.Context, _ time.Time, _ time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if limit > len(f.due) {
+		limit = len(f.due)
+	}
+	claimed := f.due[:limit]
+	f.due = f.due[limit:]
+	return claimed, nil
+}
+
+func (f *fakeStore) MarkDelivered(_ context.Context, id int64, attempts int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delivered[id] = attempts
+	return nil
+}
+
+func (f *fakeStore) MarkRetry(_ context.Context, id int64, _ int, nextAttemptAt time.Time, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.retries[id] = nextAttemptAt
+	return nil
+}
+
+func (f *fakeStore) MarkFailed(_ context.Context, id int64, _ int, lastError string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failed[id] = lastError
+	return nil
+}
+
+func TestSign(t *testing.T) {
+	got := webhook.Sign("secret", []byte(`{"id":"1"}`))
+	want := "sha256=6146142a2ce0159e84c0767881e4ec80bc397da62526e7d19f70795eb79460c0"
+
+	if got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if webhook.Sign("other", []byte(`{"id":"1"}`)) == got {
+		t.Error("expected signature to depend on the secret")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cfg := webhook.DefaultConfig()
+	cfg.BaseBackoff = time.Second
+	cfg.MaxBackoff = 10 * time.Second
+	d := webhook.NewDispatcher(newFakeStore(), cfg)
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, want := range expected {
+		if got := d.Backoff(i + 1); got != want {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+}
+
+func TestDispatchDue(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		mu.Lock()
+		received[r.Header.Get(webhook.DeliveryIDHeader)] = r.Header.Get(webhook.SignatureHeader)
+		mu.Unlock()
+
+		if r.Header.Get(webhook.SignatureHeader) != webhook.Sign("s3cret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	payload := []byte(`{"type":"pull_request.merged"}`)
+	store := newFakeStore(
+		models.WebhookDelivery{ID: 1, EventType: models.EventPullRequestMerged, Payload: payload, URL: server.URL + "/ok", Secret: "s3cret"},
+		models.WebhookDelivery{ID: 2, EventType: models.EventPullRequestMerged, Payload: payload, URL: server.URL + "/broken", Secret: "s3cret"},
+		models.WebhookDelivery{ID: 3, EventType: models.EventPullRequestMerged, Payload: payload, URL: server.URL + "/broken", Secret: "s3cret", Attempts: 7},
+	)
+
+	cfg := webhook.DefaultConfig()
+	cfg.MaxAttempts = 8
+	d := webhook.NewDispatcher(store, cfg)
+
+	if err := d.DispatchDue(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if store.delivered[1] != 1 {
+		t.Errorf("expected delivery 1 to succeed on first attempt, got %v", store.delivered)
+	}
+	if _, ok := received["1"]; !ok {
+		t.Error("expected delivery 1 to reach the server with its id header")
+	}
+	if next, ok := store.retries[2]; !ok || next.Before(time.Now()) {
+		t.Errorf("expected delivery 2 to be retried later, got %v", store.retries)
+	}
+	if _, ok := store.failed[3]; !ok {
+		t.Errorf("expected delivery 3 to fail after the last attempt, got %v", store.failed)
+	}
+}
@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"pr-reviewer-service/internal/models"
+)
+
+// Enqueuer stores deliveries of an event, implemented by repository.WebhookRepository.
+type Enqueuer interface {
+	Enqueue(ctx context.Context, event *models.Event, payload []byte) error
+}
+
+// Sink is an outbox sink that turns domain events into webhook deliveries of
+// the event's team. Enqueueing is idempotent per event, as the outbox requires.
+type Sink struct {
+	deliveries Enqueuer
+}
+
+func NewSink(deliveries Enqueuer) *Sink {
+	return &Sink{deliveries: deliveries}
+}
+
+func (s *Sink) Name() string {
+	return "webhook"
+}
+
+func (s *Sink) Handle(ctx context.Context, event *models.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.deliveries.Enqueue(ctx, event, payload)
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package tests
+
+// raceSlowdown scales timing budgets for the race detector.
+const raceSlowdown = 10